@@ -0,0 +1,361 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AlertRule is a Grafana managed alert rule of the provisioning API. Data holds the
+// queries and expressions of the rule as they are sent to Grafana.
+type AlertRule struct {
+	Id                   int64             `json:"id,omitempty"`
+	Uid                  string            `json:"uid,omitempty"`
+	OrgId                int64             `json:"orgID,omitempty"`
+	FolderUid            string            `json:"folderUID"`
+	RuleGroup            string            `json:"ruleGroup"`
+	Title                string            `json:"title"`
+	Condition            string            `json:"condition"`
+	Data                 json.RawMessage   `json:"data"`
+	NoDataState          string            `json:"noDataState,omitempty"`
+	ExecErrState         string            `json:"execErrState,omitempty"`
+	For                  string            `json:"for,omitempty"`
+	Annotations          map[string]string `json:"annotations,omitempty"`
+	Labels               map[string]string `json:"labels,omitempty"`
+	IsPaused             bool              `json:"isPaused"`
+	NotificationSettings json.RawMessage   `json:"notification_settings,omitempty"`
+	Provenance           string            `json:"provenance,omitempty"`
+	Updated              *time.Time        `json:"updated,omitempty"`
+}
+
+// AlertRuleGroup is the group of alert rules of a folder evaluated together every Interval seconds.
+type AlertRuleGroup struct {
+	Title     string      `json:"title"`
+	FolderUid string      `json:"folderUid"`
+	Interval  int64       `json:"interval"`
+	Rules     []AlertRule `json:"rules"`
+}
+
+// provisioningRequest marks the rules written by the adapter as editable in the Grafana UI
+func provisioningRequest(req *http.Request) {
+	req.Header.Set("X-Disable-Provenance", "true")
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+}
+
+// GetAlertRules returns all the alert rules of the organization the client is scoped to.
+func (c *Client) GetAlertRules(ctx context.Context) ([]AlertRule, error) {
+	slug := "/api/v1/provisioning/alert-rules"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		rules := make([]AlertRule, 0)
+		err = json.Unmarshal(body, &rules)
+		if err != nil {
+			return nil, err
+		}
+
+		return rules, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) GetAlertRule(ctx context.Context, rule *AlertRule) (*AlertRule, error) {
+	if rule == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if rule.Uid == "" {
+		return nil, newError(ErrValidation, "No Uid has been set for alert rule")
+	}
+
+	slug := "/api/v1/provisioning/alert-rules/" + url.PathEscape(rule.Uid)
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		err = json.Unmarshal(body, rule)
+		if err != nil {
+			return nil, err
+		}
+
+		return rule, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) CreateAlertRule(ctx context.Context, rule *AlertRule) (*AlertRule, error) {
+	if rule == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/v1/provisioning/alert-rules"
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(rule)
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
+	if err != nil {
+		return rule, err
+	}
+
+	provisioningRequest(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return rule, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return rule, err
+	}
+
+	if res.StatusCode == 200 || res.StatusCode == 201 {
+		err = json.Unmarshal(body, rule)
+		if err != nil {
+			return rule, err
+		}
+
+		return rule, nil
+	}
+
+	return rule, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) UpdateAlertRule(ctx context.Context, rule *AlertRule) (*AlertRule, error) {
+	if rule == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if rule.Uid == "" {
+		return nil, newError(ErrValidation, "No Uid has been set for alert rule")
+	}
+
+	slug := "/api/v1/provisioning/alert-rules/" + url.PathEscape(rule.Uid)
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(rule)
+
+	req, err := c.newRequest(ctx, http.MethodPut, slug, payloadBuffer)
+	if err != nil {
+		return rule, err
+	}
+
+	provisioningRequest(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return rule, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return rule, err
+	}
+
+	if res.StatusCode == 200 {
+		err = json.Unmarshal(body, rule)
+		if err != nil {
+			return rule, err
+		}
+
+		return rule, nil
+	}
+
+	return rule, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) DeleteAlertRule(ctx context.Context, rule *AlertRule) (bool, error) {
+	if rule == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/v1/provisioning/alert-rules/" + url.PathEscape(rule.Uid)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
+	if err != nil {
+		return false, err
+	}
+
+	provisioningRequest(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 || res.StatusCode == 204 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) GetAlertRuleGroup(ctx context.Context, group *AlertRuleGroup) (*AlertRuleGroup, error) {
+	if group == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/v1/provisioning/folder/" + url.PathEscape(group.FolderUid) + "/rule-groups/" + url.PathEscape(group.Title)
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		err = json.Unmarshal(body, group)
+		if err != nil {
+			return nil, err
+		}
+
+		return group, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// SetAlertRuleGroup replaces the rule group with the group: its interval and its rules, rules
+// without Uid are created and the rules of the group missing from group.Rules are deleted.
+func (c *Client) SetAlertRuleGroup(ctx context.Context, group *AlertRuleGroup) (*AlertRuleGroup, error) {
+	if group == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	for i := range group.Rules {
+		group.Rules[i].FolderUid = group.FolderUid
+		group.Rules[i].RuleGroup = group.Title
+	}
+
+	slug := "/api/v1/provisioning/folder/" + url.PathEscape(group.FolderUid) + "/rule-groups/" + url.PathEscape(group.Title)
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(group)
+
+	req, err := c.newRequest(ctx, http.MethodPut, slug, payloadBuffer)
+	if err != nil {
+		return group, err
+	}
+
+	provisioningRequest(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return group, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return group, err
+	}
+
+	if res.StatusCode == 200 {
+		err = json.Unmarshal(body, group)
+		if err != nil {
+			return group, err
+		}
+
+		return group, nil
+	}
+
+	return group, newResponseError(res.StatusCode, body)
+}
+
+// DeleteAlertRuleGroup deletes the rule group along with all its rules.
+func (c *Client) DeleteAlertRuleGroup(ctx context.Context, group *AlertRuleGroup) (bool, error) {
+	if group == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/v1/provisioning/folder/" + url.PathEscape(group.FolderUid) + "/rule-groups/" + url.PathEscape(group.Title)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
+	if err != nil {
+		return false, err
+	}
+
+	provisioningRequest(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 || res.StatusCode == 204 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
@@ -0,0 +1,177 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Annotation marks a point (Time) or a region (Time to TimeEnd) in epoch milliseconds on the
+// graphs of an organization, or of a single dashboard (and panel) when DashboardUid is set.
+type Annotation struct {
+	Id           int64    `json:"id,omitempty"`
+	DashboardUid string   `json:"dashboardUID,omitempty"`
+	PanelId      int64    `json:"panelId,omitempty"`
+	Time         int64    `json:"time,omitempty"`
+	TimeEnd      int64    `json:"timeEnd,omitempty"`
+	Tags         []string `json:"tags"`
+	Text         string   `json:"text"`
+	UserId       int64    `json:"userId,omitempty"`
+	Login        string   `json:"login,omitempty"`
+	Created      int64    `json:"created,omitempty"`
+	Updated      int64    `json:"updated,omitempty"`
+}
+
+// AnnotationQuery filters the annotations, every set field has to match. From and To are
+// epoch milliseconds, Tags have to match all unless MatchAny, Limit is 100 by default.
+type AnnotationQuery struct {
+	From         int64
+	To           int64
+	Tags         []string
+	MatchAny     bool
+	DashboardUid string
+	Limit        int
+}
+
+// GetAnnotations returns the annotations matching the query of the organization the client is scoped to, latest first.
+func (c *Client) GetAnnotations(ctx context.Context, query AnnotationQuery) ([]Annotation, error) {
+	slug := "/api/annotations"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	q := req.URL.Query()
+	q.Add("type", "annotation")
+	if query.From > 0 {
+		q.Add("from", strconv.FormatInt(query.From, 10))
+	}
+	if query.To > 0 {
+		q.Add("to", strconv.FormatInt(query.To, 10))
+	}
+	for _, tag := range query.Tags {
+		q.Add("tags", tag)
+	}
+	if query.MatchAny {
+		q.Add("matchAny", "true")
+	}
+	if query.DashboardUid != "" {
+		q.Add("dashboardUID", query.DashboardUid)
+	}
+	if query.Limit > 0 {
+		q.Add("limit", strconv.Itoa(query.Limit))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		annotations := make([]Annotation, 0)
+		err = json.Unmarshal(body, &annotations)
+		if err != nil {
+			return nil, err
+		}
+
+		return annotations, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// CreateAnnotation creates the annotation and sets its Id, Grafana sets Time to now when it is 0.
+func (c *Client) CreateAnnotation(ctx context.Context, annotation *Annotation) (*Annotation, error) {
+	if annotation == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if annotation.Text == "" {
+		return annotation, newError(ErrValidation, "No Text has been set for annotation")
+	}
+
+	slug := "/api/annotations"
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(annotation)
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
+	if err != nil {
+		return annotation, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return annotation, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return annotation, err
+	}
+
+	if res.StatusCode == 200 {
+		var data struct {
+			Id int64 `json:"id"`
+		}
+		err = json.Unmarshal(body, &data)
+		if err != nil {
+			return annotation, err
+		}
+		annotation.Id = data.Id
+
+		return annotation, nil
+	}
+
+	return annotation, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) DeleteAnnotation(ctx context.Context, annotation *Annotation) (bool, error) {
+	if annotation == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	} else if annotation.Id == 0 {
+		return false, newError(ErrValidation, "No Id has been set for annotation")
+	}
+
+	slug := "/api/annotations/" + strconv.FormatInt(annotation.Id, 10)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
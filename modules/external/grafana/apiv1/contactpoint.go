@@ -0,0 +1,211 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ContactPoint is an integration (email, slack, webhook, ...) alerts are sent to. The
+// contact points sharing a Name make up the receiver of that name in the notification
+// policies. Settings holds the options of the integration Type as they are sent to Grafana.
+type ContactPoint struct {
+	Uid                   string          `json:"uid,omitempty"`
+	Name                  string          `json:"name"`
+	Type                  string          `json:"type"`
+	Settings              json.RawMessage `json:"settings"`
+	DisableResolveMessage bool            `json:"disableResolveMessage"`
+	Provenance            string          `json:"provenance,omitempty"`
+}
+
+// GetContactPoints returns the contact points named name (every contact point if it is empty)
+// of the organization the client is scoped to.
+func (c *Client) GetContactPoints(ctx context.Context, name string) ([]ContactPoint, error) {
+	slug := "/api/v1/provisioning/contact-points"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	if name != "" {
+		q := req.URL.Query()
+		q.Add("name", name)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		contactPoints := make([]ContactPoint, 0)
+		err = json.Unmarshal(body, &contactPoints)
+		if err != nil {
+			return nil, err
+		}
+
+		return contactPoints, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// GetContactPoint looks the contact point up by Uid, or by Name when no Uid is set. A name
+// shared by several contact points is a conflict, they are told apart by their Uid.
+func (c *Client) GetContactPoint(ctx context.Context, contactPoint *ContactPoint) (*ContactPoint, error) {
+	if contactPoint == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if contactPoint.Uid == "" && contactPoint.Name == "" {
+		return nil, newError(ErrValidation, "No Uid, Name has been set for contact point")
+	}
+
+	// Grafana only filters the contact points by name
+	contactPoints, err := c.GetContactPoints(ctx, contactPoint.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]ContactPoint, 0, 1)
+	for _, existing := range contactPoints {
+		if (contactPoint.Uid != "" && existing.Uid == contactPoint.Uid) || (contactPoint.Uid == "" && existing.Name == contactPoint.Name) {
+			found = append(found, existing)
+		}
+	}
+	if len(found) == 0 {
+		return nil, ErrNotFound
+	} else if len(found) > 1 {
+		return nil, newError(ErrConflict, "Several contact points are named "+contactPoint.Name+", address it by uid")
+	}
+
+	*contactPoint = found[0]
+	return contactPoint, nil
+}
+
+func (c *Client) CreateContactPoint(ctx context.Context, contactPoint *ContactPoint) (*ContactPoint, error) {
+	if contactPoint == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/v1/provisioning/contact-points"
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(contactPoint)
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
+	if err != nil {
+		return contactPoint, err
+	}
+
+	provisioningRequest(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return contactPoint, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return contactPoint, err
+	}
+
+	if res.StatusCode == 200 || res.StatusCode == 202 {
+		err = json.Unmarshal(body, contactPoint)
+		if err != nil {
+			return contactPoint, err
+		}
+
+		return contactPoint, nil
+	}
+
+	return contactPoint, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) UpdateContactPoint(ctx context.Context, contactPoint *ContactPoint) (*ContactPoint, error) {
+	if contactPoint == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if contactPoint.Uid == "" {
+		return nil, newError(ErrValidation, "No Uid has been set for contact point")
+	}
+
+	slug := "/api/v1/provisioning/contact-points/" + url.PathEscape(contactPoint.Uid)
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(contactPoint)
+
+	req, err := c.newRequest(ctx, http.MethodPut, slug, payloadBuffer)
+	if err != nil {
+		return contactPoint, err
+	}
+
+	provisioningRequest(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return contactPoint, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return contactPoint, err
+	}
+
+	// Grafana answers with a message only
+	if res.StatusCode == 200 || res.StatusCode == 202 {
+		return contactPoint, nil
+	}
+
+	return contactPoint, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) DeleteContactPoint(ctx context.Context, contactPoint *ContactPoint) (bool, error) {
+	if contactPoint == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	} else if contactPoint.Uid == "" {
+		return false, newError(ErrValidation, "No Uid has been set for contact point")
+	}
+
+	slug := "/api/v1/provisioning/contact-points/" + url.PathEscape(contactPoint.Uid)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
+	if err != nil {
+		return false, err
+	}
+
+	provisioningRequest(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 || res.StatusCode == 202 || res.StatusCode == 204 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
@@ -2,34 +2,220 @@ package apiv1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
+	"reflect"
 	"strconv"
 )
 
+// DashboardModel is the dashboard JSON. Only some of its fields are typed, the JSON it
+// has been decoded from is kept and encoded back with the changes made to the typed fields,
+// so every other field (annotations, templating, time, ...) survives a round-trip.
 type DashboardModel struct {
-	Id            int64         `json:"id,omitempty"`
-	Uid           string        `json:"uid,omitempty"`
-	Panels        []interface{} `json:"panels,omitempty"`
-	Title         string        `json:"title"`
-	Tags          []string      `json:"tags,omitempty"`
-	Timezone      string        `json:"timezone,omitempty"`
-	SchemaVersion int           `json:"schemaVersion,omitempty"`
-	Version       int           `json:"version,omitempty"`
-	Refresh       string        `json:"refresh,omitempty"`
+	Id            int64             `json:"id,omitempty"`
+	Uid           string            `json:"uid,omitempty"`
+	Panels        []json.RawMessage `json:"panels,omitempty"`
+	Title         string            `json:"title"`
+	Tags          []string          `json:"tags,omitempty"`
+	Timezone      string            `json:"timezone,omitempty"`
+	SchemaVersion int               `json:"schemaVersion,omitempty"`
+	Version       int               `json:"version,omitempty"`
+	Refresh       string            `json:"refresh,omitempty"`
+
+	raw json.RawMessage
+}
+
+// dashboardModelFields has the fields of DashboardModel without its JSON methods
+type dashboardModelFields DashboardModel
+
+func (m *DashboardModel) UnmarshalJSON(data []byte) error {
+	var fields dashboardModelFields
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	*m = DashboardModel(fields)
+	m.raw = append(json.RawMessage(nil), data...)
+
+	return nil
+}
+
+func (m DashboardModel) MarshalJSON() ([]byte, error) {
+	if m.raw == nil {
+		return marshalUnescaped(dashboardModelFields(m))
+	}
+
+	// the typed fields in the order of the struct, a zero field is left out as with omitempty
+	typedFields := []struct {
+		key   string
+		value interface{}
+		zero  bool
+	}{
+		{"id", m.Id, m.Id == 0},
+		{"uid", m.Uid, m.Uid == ""},
+		{"panels", m.Panels, len(m.Panels) == 0},
+		{"title", m.Title, false},
+		{"tags", m.Tags, len(m.Tags) == 0},
+		{"timezone", m.Timezone, m.Timezone == ""},
+		{"schemaVersion", m.SchemaVersion, m.SchemaVersion == 0},
+		{"version", m.Version, m.Version == 0},
+		{"refresh", m.Refresh, m.Refresh == ""},
+	}
+
+	keys, rawFields, err := decodeObject(m.raw)
+	if err != nil {
+		return marshalUnescaped(dashboardModelFields(m))
+	}
+
+	// the raw value is kept unless the typed field has been changed, so that numbers
+	// and nested objects are encoded exactly as they have been received, a cleared
+	// field drops its raw value
+	for _, field := range typedFields {
+		rawValue, ok := rawFields[field.key]
+		if ok && field.zero && jsonZero(rawValue) {
+			continue
+		}
+		if field.zero {
+			delete(rawFields, field.key)
+			continue
+		}
+
+		value, err := marshalUnescaped(field.value)
+		if err != nil {
+			return nil, err
+		}
+		if ok && jsonEqual(rawValue, value) {
+			continue
+		}
+		if !ok {
+			keys = append(keys, field.key)
+		}
+		rawFields[field.key] = value
+	}
+
+	buffer := new(bytes.Buffer)
+	buffer.WriteByte('{')
+	for _, key := range keys {
+		value, ok := rawFields[key]
+		if !ok {
+			continue
+		}
+		if buffer.Len() > 1 {
+			buffer.WriteByte(',')
+		}
+		encodedKey, err := marshalUnescaped(key)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(encodedKey)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// Raw returns the JSON the dashboard model has been decoded from, nil if it has been built in code.
+func (m DashboardModel) Raw() json.RawMessage {
+	return m.raw
+}
+
+// decodeObject returns the keys of the JSON object in their order along with their raw values.
+func decodeObject(data []byte) ([]string, map[string]json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, nil, err
+	} else if token != json.Delim('{') {
+		return nil, nil, errors.New("Not a JSON object")
+	}
+
+	keys := make([]string, 0)
+	values := make(map[string]json.RawMessage)
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, _ := token.(string)
+
+		var value json.RawMessage
+		err = decoder.Decode(&value)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+
+	return keys, values, nil
+}
+
+// marshalUnescaped encodes the value leaving <, > and & as they are, as they are in the raw JSON.
+func marshalUnescaped(v interface{}) (json.RawMessage, error) {
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimRight(buffer.Bytes(), "\n"), nil
+}
+
+// jsonEqual reports whether both JSON values decode to the same value.
+func jsonEqual(a, b json.RawMessage) bool {
+	var valueA, valueB interface{}
+	if json.Unmarshal(a, &valueA) != nil || json.Unmarshal(b, &valueB) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(valueA, valueB)
+}
+
+// jsonZero reports whether the JSON value is null or the zero value of its type.
+func jsonZero(data json.RawMessage) bool {
+	var value interface{}
+	if json.Unmarshal(data, &value) != nil {
+		return false
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+
+	return false
 }
 
 type DashboardMeta struct {
-	FolderId  int64  `json:"folderId,omitempty"`
-	FolderUid string `json:"folderUid,omitempty"`
-	Url       string `json:"url,omitempty"`
-	IsStarred bool   `json:"isStarred,omitempty"`
-	IsHome    bool   `json:"isHome,omitempty"`
-	CanSave   bool   `json:"canSave,omitempty"`
-	CanEdit   bool   `json:"canEdit,omitempty"`
-	CanStar   bool   `json:"canStar,omitempty"`
+	FolderId    int64  `json:"folderId,omitempty"`
+	FolderUid   string `json:"folderUid,omitempty"`
+	FolderTitle string `json:"folderTitle,omitempty"`
+	Url         string `json:"url,omitempty"`
+	IsStarred   bool   `json:"isStarred,omitempty"`
+	IsHome      bool   `json:"isHome,omitempty"`
+	CanSave     bool   `json:"canSave,omitempty"`
+	CanEdit     bool   `json:"canEdit,omitempty"`
+	CanStar     bool   `json:"canStar,omitempty"`
 }
 
 type Dashboard struct {
@@ -41,33 +227,28 @@ type Dashboard struct {
 	Overwrite bool           `json:"overwrite,omitempty"`
 }
 
-func UpdateDashboardForUser(user *User, dashboard *Dashboard) (*Dashboard, error) {
-	if user.Login == "" {
-		return nil, errors.New("User login must be set")
-	}
-	if user.Password == "" {
-		return nil, errors.New("User password must be set")
-	}
+func (c *Client) UpdateDashboard(ctx context.Context, dashboard *Dashboard) (*Dashboard, error) {
 	if dashboard == nil {
-		return nil, errors.New("Nil pointer")
+		return nil, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/dashboards/db"
-	url := grafanaClientSettings.url + slug
 
+	// the dashboard JSON is sent the way it has been received, <, > and & unescaped
 	payloadBuffer := new(bytes.Buffer)
-	json.NewEncoder(payloadBuffer).Encode(dashboard)
+	encoder := json.NewEncoder(payloadBuffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(dashboard)
 
-	req, err := http.NewRequest(http.MethodPost, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(user.Login, user.Password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -89,37 +270,32 @@ func UpdateDashboardForUser(user *User, dashboard *Dashboard) (*Dashboard, error
 		if data["message"] == "Dashboard added" {
 			dashboard.Dashboard.Id = int64(data["id"].(float64))
 		}
+		if version, ok := data["version"].(float64); ok {
+			dashboard.Dashboard.Version = int(version)
+		}
 
 		return dashboard, nil
 	}
 
-	return dashboard, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return dashboard, newResponseError(res.StatusCode, body)
 }
 
-func DeleteDashboardForUser(user *User, dashboard *Dashboard) (bool, error) {
-	if user.Login == "" {
-		return false, errors.New("User login must be set")
-	}
-	if user.Password == "" {
-		return false, errors.New("User password must be set")
-	}
+func (c *Client) DeleteDashboard(ctx context.Context, dashboard *Dashboard) (bool, error) {
 	if dashboard == nil {
-		return false, errors.New("Nil pointer")
+		return false, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/dashboards/uid/" + dashboard.Dashboard.Uid
-	url := grafanaClientSettings.url + slug
 
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(user.Login, user.Password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -141,34 +317,26 @@ func DeleteDashboardForUser(user *User, dashboard *Dashboard) (bool, error) {
 		return true, nil
 	}
 
-	return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return false, newResponseError(res.StatusCode, body)
 }
 
-func GetDashboardForUser(user *User, dashboard *Dashboard) (*Dashboard, error) {
-	if user.Login == "" {
-		return nil, errors.New("User login must be set")
-	}
-	if user.Password == "" {
-		return nil, errors.New("User password must be set")
-	}
+func (c *Client) GetDashboard(ctx context.Context, dashboard *Dashboard) (*Dashboard, error) {
 	slug := ""
 	if dashboard.Dashboard.Id > 0 {
 		slug = "/api/search/?dashboardIds=" + strconv.FormatInt(dashboard.Dashboard.Id, 10)
 	} else if len(dashboard.Dashboard.Title) > 0 {
 		slug = "/api/search/?query=" + dashboard.Dashboard.Title
 	}
-	url := grafanaClientSettings.url + slug
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(user.Login, user.Password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -190,36 +358,28 @@ func GetDashboardForUser(user *User, dashboard *Dashboard) (*Dashboard, error) {
 			dashboard.Dashboard = dashboardsResponse[0]
 			return dashboard, nil
 		} else {
-			return nil, errors.New("Dashboard not found")
+			return nil, ErrNotFound
 		}
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
 }
 
-func GetDashboardForUserByUid(user *User, dashboard *Dashboard) (*Dashboard, error) {
-	if user.Login == "" {
-		return nil, errors.New("User login must be set")
-	}
-	if user.Password == "" {
-		return nil, errors.New("User password must be set")
-	}
+func (c *Client) GetDashboardByUid(ctx context.Context, dashboard *Dashboard) (*Dashboard, error) {
 	slug := "/api/dashboards/uid/" + dashboard.Dashboard.Uid
-	url := grafanaClientSettings.url + slug
 
 	payloadBuffer := new(bytes.Buffer)
 	json.NewEncoder(payloadBuffer).Encode(dashboard)
 
-	req, err := http.NewRequest(http.MethodGet, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, payloadBuffer)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(user.Login, user.Password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -239,58 +399,49 @@ func GetDashboardForUserByUid(user *User, dashboard *Dashboard) (*Dashboard, err
 
 		return dashboard, nil
 	} else if res.StatusCode == 404 {
-		return dashboard, errors.New("Empty result")
+		return dashboard, ErrNotFound
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
 }
 
-func GetDashboardsForUser(user *User) (*[]Dashboard, error) {
-	if user.Login == "" {
-		return nil, errors.New("User login must be set")
-	}
-	if user.Password == "" {
-		return nil, errors.New("User password must be set")
-	}
-	slug := "/api/search/?type=dash-db"
-	url := grafanaClientSettings.url + slug
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+// GetDashboards returns all the dashboards, walking every page of them.
+func (c *Client) GetDashboards(ctx context.Context) (*[]Dashboard, error) {
+	dashboards := make([]Dashboard, 0)
+	err := c.WalkDashboards(ctx, DefaultPerPage, func(page []Dashboard) error {
+		dashboards = append(dashboards, page...)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(user.Login, user.Password)
+	return &dashboards, nil
+}
 
-	res, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
+// WalkDashboards calls fn with every page of perPage dashboards until fn returns an error.
+func (c *Client) WalkDashboards(ctx context.Context, perPage int, fn func(dashboards []Dashboard) error) error {
+	return walkPages(perPage, func(page Page) (int, int64, error) {
+		dashboards, err := c.GetDashboardsPage(ctx, page)
+		if err != nil {
+			return 0, 0, err
+		}
 
-	defer res.Body.Close()
+		return len(dashboards), -1, fn(dashboards)
+	})
+}
 
-	body, err := io.ReadAll(res.Body)
+// GetDashboardsPage returns a page of the dashboard summaries, along with their folder and url.
+func (c *Client) GetDashboardsPage(ctx context.Context, page Page) ([]Dashboard, error) {
+	hits, err := c.SearchDashboardsPage(ctx, DashboardSearch{}, page)
 	if err != nil {
 		return nil, err
 	}
 
-	if res.StatusCode == 200 {
-		var dashboardsResponse []DashboardModel
-		var dashboards []Dashboard
-		err = json.Unmarshal(body, &dashboardsResponse)
-
-		if err != nil {
-			return nil, err
-		}
-
-		for _, entity := range dashboardsResponse {
-			dashboards = append(dashboards, Dashboard{Dashboard: entity})
-		}
-
-		return &dashboards, nil
+	dashboards := make([]Dashboard, 0, len(hits))
+	for _, hit := range hits {
+		dashboards = append(dashboards, hit.Dashboard())
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return dashboards, nil
 }
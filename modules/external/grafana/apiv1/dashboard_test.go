@@ -0,0 +1,152 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// captureTransport answers every request with a saved dashboard and keeps the request body.
+type captureTransport struct {
+	body []byte
+}
+
+func (t *captureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	t.body = body
+
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"id":17,"slug":"dashboard","status":"success","uid":"dashboard","url":"/d/dashboard/dashboard","version":2}`)),
+		Request:    req,
+	}, nil
+}
+
+// updateDashboard saves the dashboard model through UpdateDashboard and returns the
+// dashboard JSON of the request sent to Grafana.
+func updateDashboard(t *testing.T, model DashboardModel) []byte {
+	t.Helper()
+
+	transport := &captureTransport{}
+	client := NewClient("http://grafana.local", "admin", "admin", WithTransport(transport))
+
+	_, err := client.UpdateDashboard(context.Background(), &Dashboard{Dashboard: model, Overwrite: true})
+	if err != nil {
+		t.Fatalf("UpdateDashboard: %v", err)
+	}
+
+	var payload struct {
+		Dashboard json.RawMessage `json:"dashboard"`
+	}
+	err = json.Unmarshal(transport.body, &payload)
+	if err != nil {
+		t.Fatalf("Unmarshal request body %s: %v", transport.body, err)
+	}
+
+	return payload.Dashboard
+}
+
+// readExport returns the compacted dashboard JSON exported from Grafana along with its model.
+func readExport(t *testing.T, name string) ([]byte, DashboardModel) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	compact := new(bytes.Buffer)
+	err = json.Compact(compact, data)
+	if err != nil {
+		t.Fatalf("Compact %s: %v", name, err)
+	}
+
+	var model DashboardModel
+	err = json.Unmarshal(compact.Bytes(), &model)
+	if err != nil {
+		t.Fatalf("Unmarshal %s: %v", name, err)
+	}
+
+	return compact.Bytes(), model
+}
+
+func TestUpdateDashboardRoundTrip(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("No exported dashboards: %v", err)
+	}
+
+	for _, path := range paths {
+		name := filepath.Base(path)
+		t.Run(name, func(t *testing.T) {
+			export, model := readExport(t, name)
+
+			got := updateDashboard(t, model)
+			if !bytes.Equal(got, export) {
+				t.Errorf("Round-trip differs\n got: %s\nwant: %s", got, export)
+			}
+		})
+	}
+}
+
+func TestUpdateDashboardChangedFields(t *testing.T) {
+	export, model := readExport(t, "checkout-service.json")
+	model.Title = "Checkout <prod> & staging"
+	model.Version = 10
+
+	want := bytes.Replace(export, []byte(`"title":"Checkout Service","uid"`), []byte(`"title":"Checkout <prod> & staging","uid"`), 1)
+	want = bytes.Replace(want, []byte(`"version":9,`), []byte(`"version":10,`), 1)
+
+	got := updateDashboard(t, model)
+	if !bytes.Equal(got, want) {
+		t.Errorf("Changed fields are not sent in place\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestUpdateDashboardClearedFields(t *testing.T) {
+	export, model := readExport(t, "checkout-service.json")
+
+	// a copy of the dashboard is saved without its id, uid and version
+	model.Id = 0
+	model.Uid = ""
+	model.Version = 0
+
+	want := bytes.Replace(export, []byte(`"id":17,`), nil, 1)
+	want = bytes.Replace(want, []byte(`"uid":"checkout-svc",`), nil, 1)
+	want = bytes.Replace(want, []byte(`"version":9,`), nil, 1)
+
+	got := updateDashboard(t, model)
+	if !bytes.Equal(got, want) {
+		t.Errorf("Cleared fields are sent\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestUpdateDashboardKeepsNullId(t *testing.T) {
+	_, model := readExport(t, "node-overview-external.json")
+
+	got := updateDashboard(t, model)
+	if !bytes.Contains(got, []byte(`"id":null,`)) {
+		t.Errorf("The null id of a dashboard exported for sharing externally is not kept: %s", got)
+	}
+}
+
+func TestUpdateDashboardWithoutRaw(t *testing.T) {
+	model := DashboardModel{Title: "Latency <p99> & errors", Tags: []string{"sre"}}
+
+	got := updateDashboard(t, model)
+	want := `{"title":"Latency <p99> & errors","tags":["sre"]}`
+	if string(got) != want {
+		t.Errorf("Built dashboard\n got: %s\nwant: %s", got, want)
+	}
+}
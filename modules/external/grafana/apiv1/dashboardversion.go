@@ -0,0 +1,298 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DashboardVersion is a saved version of a dashboard, Data holds the whole dashboard JSON of
+// the version and is only set for a single version.
+type DashboardVersion struct {
+	Id            int64           `json:"id"`
+	DashboardId   int64           `json:"dashboardId,omitempty"`
+	DashboardUid  string          `json:"dashboardUid,omitempty"`
+	ParentVersion int             `json:"parentVersion"`
+	RestoredFrom  int             `json:"restoredFrom"`
+	Version       int             `json:"version"`
+	Created       time.Time       `json:"created"`
+	CreatedBy     string          `json:"createdBy"`
+	Message       string          `json:"message"`
+	Data          json.RawMessage `json:"data,omitempty"`
+}
+
+// Operations of a dashboard change
+const (
+	ChangeAdd     = "add"
+	ChangeRemove  = "remove"
+	ChangeReplace = "replace"
+)
+
+// DashboardChange is a difference between two versions of a dashboard JSON at Path, a JSON
+// pointer (/panels/0/title). From is the value of the older version, To of the newer one,
+// null when the change adds or removes the value (and when the value is null).
+type DashboardChange struct {
+	Op   string          `json:"op"`
+	Path string          `json:"path"`
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// GetDashboardVersions returns all the versions of the dashboard, latest first.
+func (c *Client) GetDashboardVersions(ctx context.Context, dashboard *Dashboard) ([]DashboardVersion, error) {
+	if dashboard == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if dashboard.Dashboard.Uid == "" {
+		return nil, newError(ErrValidation, "No Uid has been set for dashboard")
+	}
+
+	versions := make([]DashboardVersion, 0)
+	continueToken := ""
+	for {
+		slug := "/api/dashboards/uid/" + dashboard.Dashboard.Uid + "/versions"
+
+		req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Add("Accept", "application/json")
+
+		q := req.URL.Query()
+		q.Add("limit", strconv.Itoa(DefaultPerPage))
+		if continueToken != "" {
+			q.Add("continueToken", continueToken)
+		} else {
+			q.Add("start", strconv.Itoa(len(versions)))
+		}
+		req.URL.RawQuery = q.Encode()
+
+		res, err := c.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if res.StatusCode != 200 {
+			return nil, newResponseError(res.StatusCode, body)
+		}
+
+		// Grafana 11 wraps the versions along with the token of the next page
+		var data struct {
+			ContinueToken string             `json:"continueToken"`
+			Versions      []DashboardVersion `json:"versions"`
+		}
+		if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			err = json.Unmarshal(body, &data.Versions)
+		} else {
+			err = json.Unmarshal(body, &data)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, data.Versions...)
+		continueToken = data.ContinueToken
+		if (continueToken == "" && len(data.Versions) < DefaultPerPage) || len(data.Versions) == 0 {
+			return versions, nil
+		}
+	}
+}
+
+// GetDashboardVersion returns the version of the dashboard along with its dashboard JSON.
+func (c *Client) GetDashboardVersion(ctx context.Context, dashboard *Dashboard, version int) (*DashboardVersion, error) {
+	if dashboard == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if dashboard.Dashboard.Uid == "" {
+		return nil, newError(ErrValidation, "No Uid has been set for dashboard")
+	}
+
+	slug := "/api/dashboards/uid/" + dashboard.Dashboard.Uid + "/versions/" + strconv.Itoa(version)
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		dashboardVersion := &DashboardVersion{}
+		err = json.Unmarshal(body, dashboardVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		return dashboardVersion, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// RestoreDashboardVersion saves the dashboard JSON of the version as a new version of the
+// dashboard and sets the Version of the dashboard to it.
+func (c *Client) RestoreDashboardVersion(ctx context.Context, dashboard *Dashboard, version int) (bool, error) {
+	if dashboard == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	} else if dashboard.Dashboard.Uid == "" {
+		return false, newError(ErrValidation, "No Uid has been set for dashboard")
+	}
+
+	slug := "/api/dashboards/uid/" + dashboard.Dashboard.Uid + "/restore"
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(map[string]interface{}{
+		"version": version,
+	})
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 {
+		var data struct {
+			Version int `json:"version"`
+		}
+		err = json.Unmarshal(body, &data)
+		if err != nil {
+			return false, err
+		}
+		dashboard.Dashboard.Version = data.Version
+
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
+
+// DiffDashboardVersions returns the changes of the dashboard JSON from the version from to the
+// version to, ordered by path. Arrays (panels, ...) are compared item by item.
+func (c *Client) DiffDashboardVersions(ctx context.Context, dashboard *Dashboard, from, to int) ([]DashboardChange, error) {
+	values := make([]interface{}, 2)
+	for i, version := range []int{from, to} {
+		dashboardVersion, err := c.GetDashboardVersion(ctx, dashboard, version)
+		if err != nil {
+			return nil, err
+		}
+
+		// numbers are kept as they are written rather than turned into float64
+		decoder := json.NewDecoder(bytes.NewReader(dashboardVersion.Data))
+		decoder.UseNumber()
+		err = decoder.Decode(&values[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return diffJSON("", values[0], values[1], make([]DashboardChange, 0)), nil
+}
+
+func diffJSON(path string, from, to interface{}, changes []DashboardChange) []DashboardChange {
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		toValue, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(fromValue)+len(toValue))
+		for key := range fromValue {
+			keys = append(keys, key)
+		}
+		for key := range toValue {
+			if _, ok := fromValue[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			keyPath := path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+			fromItem, inFrom := fromValue[key]
+			toItem, inTo := toValue[key]
+			switch {
+			case !inTo:
+				changes = append(changes, DashboardChange{Op: ChangeRemove, Path: keyPath, From: rawJSON(fromItem)})
+			case !inFrom:
+				changes = append(changes, DashboardChange{Op: ChangeAdd, Path: keyPath, To: rawJSON(toItem)})
+			default:
+				changes = diffJSON(keyPath, fromItem, toItem, changes)
+			}
+		}
+		return changes
+	case []interface{}:
+		toValue, ok := to.([]interface{})
+		if !ok {
+			break
+		}
+
+		for i := 0; i < len(fromValue) || i < len(toValue); i++ {
+			itemPath := path + "/" + strconv.Itoa(i)
+			switch {
+			case i >= len(toValue):
+				changes = append(changes, DashboardChange{Op: ChangeRemove, Path: itemPath, From: rawJSON(fromValue[i])})
+			case i >= len(fromValue):
+				changes = append(changes, DashboardChange{Op: ChangeAdd, Path: itemPath, To: rawJSON(toValue[i])})
+			default:
+				changes = diffJSON(itemPath, fromValue[i], toValue[i], changes)
+			}
+		}
+		return changes
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		changes = append(changes, DashboardChange{Op: ChangeReplace, Path: path, From: rawJSON(from), To: rawJSON(to)})
+	}
+
+	return changes
+}
+
+// rawJSON encodes back a value decoded from a dashboard JSON, a null value as null.
+func rawJSON(value interface{}) json.RawMessage {
+	raw, err := marshalUnescaped(value)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+
+	return raw
+}
@@ -0,0 +1,48 @@
+package apiv1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiffDashboardVersions(t *testing.T) {
+	versions := map[string]string{
+		"1": `{"title":"CPU","interval":null,"refresh":"1m","tags":[],"panels":[{"title":"Load"},{"title":"Busy <user> & system"}]}`,
+		"2": `{"title":"CPU load","interval":"1m","refresh":null,"tags":["prod"],"panels":[{"title":"Load"}],"decimals":1.50}`,
+	}
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		for version, data := range versions {
+			if r.URL.Path == "/api/dashboards/uid/cpu/versions/"+version {
+				fmt.Fprintf(w, `{"id":%s,"dashboardId":7,"version":%s,"data":%s}`, version, version, data)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Dashboard version not found"}`)
+	}))
+	defer stub.Close()
+
+	client := NewClient(stub.URL, "admin", "admin")
+	changes, err := client.DiffDashboardVersions(context.Background(), &Dashboard{Dashboard: DashboardModel{Uid: "cpu"}}, 1, 2)
+	if err != nil {
+		t.Fatalf("DiffDashboardVersions: %v", err)
+	}
+
+	got, err := marshalUnescaped(changes)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	want := `[{"op":"add","path":"/decimals","from":null,"to":1.50},` +
+		`{"op":"replace","path":"/interval","from":null,"to":"1m"},` +
+		`{"op":"remove","path":"/panels/1","from":{"title":"Busy <user> & system"},"to":null},` +
+		`{"op":"replace","path":"/refresh","from":"1m","to":null},` +
+		`{"op":"add","path":"/tags/0","from":null,"to":"prod"},` +
+		`{"op":"replace","path":"/title","from":"CPU","to":"CPU load"}]`
+	if string(got) != want {
+		t.Errorf("Changes\n got: %s\nwant: %s", got, want)
+	}
+}
@@ -2,8 +2,8 @@ package apiv1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"io"
 	"net/http"
 	"strconv"
@@ -33,27 +33,25 @@ type Datasource struct {
 	SecureJsonFields  interface{} `json:"secureJsonFields,omitempty"`
 }
 
-func CreateDatasourceForUser(user *User, datasource *Datasource) (*Datasource, error) {
+func (c *Client) CreateDatasource(ctx context.Context, datasource *Datasource) (*Datasource, error) {
 	if datasource == nil {
-		return nil, errors.New("Nil pointer")
+		return nil, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/datasources"
-	url := grafanaClientSettings.url + slug
 
 	payloadBuffer := new(bytes.Buffer)
 	json.NewEncoder(payloadBuffer).Encode(datasource)
 
-	req, err := http.NewRequest(http.MethodPost, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(user.Login, user.Password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -79,26 +77,24 @@ func CreateDatasourceForUser(user *User, datasource *Datasource) (*Datasource, e
 		return datasource, nil
 	}
 
-	return datasource, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return datasource, newResponseError(res.StatusCode, body)
 }
 
-func UpdateDatasourceForUser(user *User, datasource *Datasource) (bool, error) {
+func (c *Client) UpdateDatasource(ctx context.Context, datasource *Datasource) (bool, error) {
 	slug := "/api/datasources/" + strconv.FormatInt(datasource.Id, 10)
-	url := grafanaClientSettings.url + slug
 
 	payloadBuffer := new(bytes.Buffer)
 	json.NewEncoder(payloadBuffer).Encode(datasource)
 
-	req, err := http.NewRequest(http.MethodPut, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodPut, slug, payloadBuffer)
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(user.Login, user.Password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -118,18 +114,23 @@ func UpdateDatasourceForUser(user *User, datasource *Datasource) (bool, error) {
 		}
 
 		if data["message"] == "Datasource updated" {
+			if updated, ok := data["datasource"].(map[string]interface{}); ok {
+				if version, ok := updated["version"].(float64); ok {
+					datasource.Version = int(version)
+				}
+			}
 			return true, nil
 		}
 	}
 
-	return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return false, newResponseError(res.StatusCode, body)
 }
 
-func DeleteDatasourceForUser(user *User, datasource *Datasource) (bool, error) {
+func (c *Client) DeleteDatasource(ctx context.Context, datasource *Datasource) (bool, error) {
 	slug := ""
 
 	if datasource == nil {
-		return false, errors.New("Nil pointer")
+		return false, newError(ErrValidation, "Nil pointer")
 	} else if datasource.Id > 0 {
 		slug = "/api/datasources/" + strconv.FormatInt(datasource.Id, 10)
 	} else if datasource.Uid != "" {
@@ -137,21 +138,18 @@ func DeleteDatasourceForUser(user *User, datasource *Datasource) (bool, error) {
 	} else if datasource.Name != "" {
 		slug = "/api/datasources/name/" + datasource.Name
 	} else {
-		return false, errors.New("No Id, Uid, Name has been set for datasource")
+		return false, newError(ErrValidation, "No Id, Uid, Name has been set for datasource")
 	}
 
-	url := grafanaClientSettings.url + slug
-
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(user.Login, user.Password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -175,14 +173,14 @@ func DeleteDatasourceForUser(user *User, datasource *Datasource) (bool, error) {
 		}
 	}
 
-	return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return false, newResponseError(res.StatusCode, body)
 }
 
-func GetDatasourceForUser(user *User, datasource *Datasource) (*Datasource, error) {
+func (c *Client) GetDatasource(ctx context.Context, datasource *Datasource) (*Datasource, error) {
 	slug := ""
 
 	if datasource == nil {
-		return nil, errors.New("Nil pointer")
+		return nil, newError(ErrValidation, "Nil pointer")
 	} else if datasource.Id > 0 {
 		slug = "/api/datasources/" + strconv.FormatInt(datasource.Id, 10)
 	} else if datasource.Uid != "" {
@@ -190,21 +188,18 @@ func GetDatasourceForUser(user *User, datasource *Datasource) (*Datasource, erro
 	} else if datasource.Name != "" {
 		slug = "/api/datasources/name/" + datasource.Name
 	} else {
-		return nil, errors.New("No Id, Uid, Name has been set for datasource")
+		return nil, newError(ErrValidation, "No Id, Uid, Name has been set for datasource")
 	}
 
-	url := grafanaClientSettings.url + slug
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(user.Login, user.Password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -222,29 +217,27 @@ func GetDatasourceForUser(user *User, datasource *Datasource) (*Datasource, erro
 			return nil, err
 		}
 		if datasource.Id == 0 {
-			return nil, errors.New("Empty result")
+			return nil, ErrNotFound
 		}
 
 		return datasource, nil
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
 }
 
-func GetDatasourcesForUser(user *User) (*[]Datasource, error) {
+func (c *Client) GetDatasources(ctx context.Context) (*[]Datasource, error) {
 	slug := "/api/datasources"
-	url := grafanaClientSettings.url + slug
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(user.Login, user.Password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -266,5 +259,5 @@ func GetDatasourcesForUser(user *User) (*[]Datasource, error) {
 		return &datasources, nil
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
 }
@@ -0,0 +1,76 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors classifying failed calls, to be checked with errors.Is.
+var (
+	ErrNotFound   = errors.New("Empty result")
+	ErrConflict   = errors.New("Already exists")
+	ErrValidation = errors.New("Validation failed")
+	ErrPermission = errors.New("Permission denied")
+	ErrLastAdmin  = errors.New("Cannot remove last organization admin")
+	ErrUpstream   = errors.New("Grafana server error")
+)
+
+// Error is returned by every failed call. It carries the status code and message
+// Grafana responded with and unwraps to one of the sentinel errors above, so it
+// works with errors.Is and errors.As.
+type Error struct {
+	// StatusCode is the status code Grafana responded with, 0 if the call failed before a request was sent.
+	StatusCode int
+	// Message is the message field of the Grafana response, or the reason the call has been refused.
+	Message string
+	// Body is the raw Grafana response body.
+	Body string
+	// Err is the sentinel error classifying the failure, nil if it could not be classified.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode == 0 {
+		return e.Message
+	}
+
+	return "Got response: " + strconv.Itoa(e.StatusCode) + ", body: " + e.Body
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(kind error, message string) error {
+	return &Error{Message: message, Err: kind}
+}
+
+func newResponseError(statusCode int, body []byte) error {
+	e := &Error{StatusCode: statusCode, Body: string(body)}
+
+	var data struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &data) == nil {
+		e.Message = data.Message
+	}
+
+	switch {
+	case statusCode == 404:
+		e.Err = ErrNotFound
+	case e.Message == ErrLastAdmin.Error() || strings.Contains(e.Message, "no organization admin left"):
+		e.Err = ErrLastAdmin
+	case statusCode == 409 || statusCode == 412 || strings.Contains(e.Message, "already exists"):
+		e.Err = ErrConflict
+	case statusCode == 400 || statusCode == 422:
+		e.Err = ErrValidation
+	case statusCode == 401 || statusCode == 403:
+		e.Err = ErrPermission
+	case statusCode >= 500:
+		e.Err = ErrUpstream
+	}
+
+	return e
+}
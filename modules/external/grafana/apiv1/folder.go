@@ -2,9 +2,9 @@ package apiv1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
 	"strconv"
@@ -28,27 +28,25 @@ type Folder struct {
 	Overwrite bool      `json:"overwrite,omitempty"`
 }
 
-func CreateFolderForUser(user *User, folder *Folder) (*Folder, error) {
+func (c *Client) CreateFolder(ctx context.Context, folder *Folder) (*Folder, error) {
 	if folder == nil {
-		return nil, errors.New("Nil pointer")
+		return nil, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/folders"
-	url := grafanaClientSettings.url + slug
 
 	payloadBuffer := new(bytes.Buffer)
 	json.NewEncoder(payloadBuffer).Encode(folder)
 
-	req, err := http.NewRequest(http.MethodPost, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -69,26 +67,24 @@ func CreateFolderForUser(user *User, folder *Folder) (*Folder, error) {
 		return folder, nil
 	}
 
-	return folder, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return folder, newResponseError(res.StatusCode, body)
 }
 
-func UpdateFolderForUser(user *User, folder *Folder) (bool, error) {
-	slug := "/api/folder/" + folder.Uid
-	url := grafanaClientSettings.url + slug
+func (c *Client) UpdateFolder(ctx context.Context, folder *Folder) (bool, error) {
+	slug := "/api/folders/" + folder.Uid
 
 	payloadBuffer := new(bytes.Buffer)
 	json.NewEncoder(payloadBuffer).Encode(folder)
 
-	req, err := http.NewRequest(http.MethodPut, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodPut, slug, payloadBuffer)
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -100,47 +96,41 @@ func UpdateFolderForUser(user *User, folder *Folder) (bool, error) {
 		return false, err
 	}
 
+	// Grafana answers with the updated folder, its new version included
 	if res.StatusCode == 200 {
-		var data map[string]interface{}
-		err = json.Unmarshal(body, &data)
+		err = json.Unmarshal(body, folder)
 		if err != nil {
 			return false, err
 		}
 
-		if data["message"] == "Folder updated" {
-			return true, nil
-		}
+		return true, nil
 	}
 
-	return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return false, newResponseError(res.StatusCode, body)
 }
 
-func DeleteFolderForUser(user *User, folder *Folder) (bool, error) {
+func (c *Client) DeleteFolder(ctx context.Context, folder *Folder) (bool, error) {
 	if folder == nil {
-		return false, errors.New("Nil pointer")
+		return false, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/folders/" + folder.Uid
-	url := grafanaClientSettings.url + slug
 
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return false, err
 	}
 
 	defer res.Body.Close()
 
-	fmt.Printf("Results: %v\n", res)
-
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		return false, err
@@ -153,16 +143,16 @@ func DeleteFolderForUser(user *User, folder *Folder) (bool, error) {
 			return false, err
 		}
 		return true, nil
-	} else if res.StatusCode == 404 {
-		return false, errors.New("Folder not found")
 	}
 
-	return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return false, newResponseError(res.StatusCode, body)
 
 }
 
-func GetFoldersForUser(user *User, limitOptional ...int) ([]Folder, error) {
-	limit := 1000
+// GetFolders returns all the folders, walking every page of them, limitOptional sets
+// the size of the pages (DefaultPerPage by default).
+func (c *Client) GetFolders(ctx context.Context, limitOptional ...int) ([]Folder, error) {
+	limit := DefaultPerPage
 
 	folders := make([]Folder, 0)
 
@@ -170,23 +160,50 @@ func GetFoldersForUser(user *User, limitOptional ...int) ([]Folder, error) {
 		limit = limitOptional[0]
 	}
 
+	err := c.WalkFolders(ctx, limit, func(page []Folder) error {
+		folders = append(folders, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+// WalkFolders calls fn with every page of perPage folders until fn returns an error.
+func (c *Client) WalkFolders(ctx context.Context, perPage int, fn func(folders []Folder) error) error {
+	return walkPages(perPage, func(page Page) (int, int64, error) {
+		folders, err := c.GetFoldersPage(ctx, page)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		return len(folders), -1, fn(folders)
+	})
+}
+
+func (c *Client) GetFoldersPage(ctx context.Context, page Page) ([]Folder, error) {
+	page = page.normalize()
+
+	folders := make([]Folder, 0)
+
 	slug := "/api/folders/"
-	url := grafanaClientSettings.url + slug
 
-	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
 	q := req.URL.Query()
-	q.Add("limit", strconv.Itoa(limit))
+	q.Add("page", strconv.Itoa(page.Page))
+	q.Add("limit", strconv.Itoa(page.PerPage))
 	req.URL.RawQuery = q.Encode()
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -206,26 +223,24 @@ func GetFoldersForUser(user *User, limitOptional ...int) ([]Folder, error) {
 		return folders, nil
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
 }
 
-func GetFolderForUser(user *User, folder *Folder) (*Folder, error) {
+func (c *Client) GetFolder(ctx context.Context, folder *Folder) (*Folder, error) {
 	slug := "/api/folders/" + folder.Uid
-	url := grafanaClientSettings.url + slug
 
 	payloadBuffer := new(bytes.Buffer)
 	json.NewEncoder(payloadBuffer).Encode(folder)
 
-	req, err := http.NewRequest(http.MethodGet, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, payloadBuffer)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -245,29 +260,27 @@ func GetFolderForUser(user *User, folder *Folder) (*Folder, error) {
 
 		return folder, nil
 	} else if res.StatusCode == 404 {
-		return folder, errors.New("Empty result")
+		return folder, ErrNotFound
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
 }
 
-func GetFolderByIdForUser(user *User, folder *Folder) (*Folder, error) {
+func (c *Client) GetFolderById(ctx context.Context, folder *Folder) (*Folder, error) {
 	slug := "/api/folders/id/" + strconv.FormatInt(folder.Id, 10)
-	url := grafanaClientSettings.url + slug
 
 	payloadBuffer := new(bytes.Buffer)
 	json.NewEncoder(payloadBuffer).Encode(folder)
 
-	req, err := http.NewRequest(http.MethodGet, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, payloadBuffer)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -287,8 +300,40 @@ func GetFolderByIdForUser(user *User, folder *Folder) (*Folder, error) {
 
 		return folder, nil
 	} else if res.StatusCode == 404 {
-		return folder, errors.New("Empty result")
+		return folder, ErrNotFound
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// GetFolderByTitle fills the folder by its exact Title, walking the folders until it is found.
+func (c *Client) GetFolderByTitle(ctx context.Context, folder *Folder) (*Folder, error) {
+	if folder == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if folder.Title == "" {
+		return nil, newError(ErrValidation, "No Title has been set for folder")
+	}
+
+	found := false
+	err := c.WalkFolders(ctx, DefaultPerPage, func(folders []Folder) error {
+		for _, candidate := range folders {
+			if candidate.Title == folder.Title {
+				*folder = candidate
+				found = true
+				return errFolderFound
+			}
+		}
+		return nil
+	})
+	if err != nil && err != errFolderFound {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrNotFound
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return c.GetFolder(ctx, folder)
 }
+
+// errFolderFound stops walking the folders in GetFolderByTitle
+var errFolderFound = errors.New("Folder found")
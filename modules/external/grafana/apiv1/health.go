@@ -0,0 +1,49 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+type Health struct {
+	Commit   string `json:"commit"`
+	Database string `json:"database"`
+	Version  string `json:"version"`
+}
+
+func (c *Client) GetHealth(ctx context.Context) (*Health, error) {
+	slug := "/api/health"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		var health Health
+		err = json.Unmarshal(body, &health)
+		if err != nil {
+			return nil, err
+		}
+
+		return &health, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
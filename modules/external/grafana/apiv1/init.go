@@ -1,31 +1,143 @@
 package apiv1
 
 import (
+	"context"
+	"io"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
-var grafanaClientSettings = struct {
-	url      string
-	timeout  time.Duration
-	login    string
-	password string
-}{
-	url:      "http://localhost:3000",
-	timeout:  30,
-	login:    "admin",
-	password: "admin",
+// serverAdminSlugs prefix the endpoints which need the Grafana server admin,
+// an API key or a service account token belongs to a single organization instead
+var serverAdminSlugs = []string{"/api/admin/", "/api/orgs", "/api/users"}
+
+// Client talks to a single Grafana instance with a single set of credentials.
+// Several clients may be used at once, each one is safe for concurrent use.
+type Client struct {
+	url       string
+	timeout   time.Duration
+	login     string
+	password  string
+	token     string
+	orgId     int64
+	transport http.RoundTripper
+	logger    *log.Logger
+	client    *http.Client
+}
+
+// ClientOption configures optional Client parameters in NewClient.
+type ClientOption func(*Client)
+
+// WithTimeout sets the timeout of every request made by the client (30 seconds by default).
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
 }
 
-var client http.Client
+// WithTransport sets the http.RoundTripper used to reach Grafana (http.DefaultTransport by default).
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithLogger sets the logger the client reports non fatal errors to (log.Default() by default).
+func WithLogger(logger *log.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
 
-func NewClient(url, login, password string) {
-	grafanaClientSettings.url = url
-	grafanaClientSettings.login = login
-	grafanaClientSettings.password = password
-	buildClient()
+// WithToken makes the client authenticate with the bearer token (an API key or a service
+// account token) instead of the login and password. Such a client is refused server admin
+// calls (users, organizations) with ErrPermission before anything is sent.
+func WithToken(token string) ClientOption {
+	return func(c *Client) {
+		c.token = token
+	}
 }
 
-func buildClient() {
-	client = http.Client{Timeout: grafanaClientSettings.timeout * time.Second}
+func NewClient(url, login, password string, options ...ClientOption) *Client {
+	c := &Client{
+		url:      url,
+		timeout:  30 * time.Second,
+		login:    login,
+		password: password,
+		logger:   log.Default(),
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	c.client = &http.Client{
+		Timeout:   c.timeout,
+		Transport: c.transport,
+	}
+
+	return c
+}
+
+// URL returns the base url of the Grafana instance.
+func (c *Client) URL() string {
+	return c.url
+}
+
+// ForOrganization returns a copy of the client which scopes every request to the
+// organization with the X-Grafana-Org-Id header, instead of the current organization
+// of the authenticated user. The user must be a member of the organization.
+func (c *Client) ForOrganization(orgId int64) *Client {
+	scoped := *c
+	scoped.orgId = orgId
+	return &scoped
+}
+
+// AsUser returns a copy of the client which authenticates as the given user with basic auth,
+// whether the client has a token or not.
+func (c *Client) AsUser(login, password string) *Client {
+	scoped := *c
+	scoped.login = login
+	scoped.password = password
+	scoped.token = ""
+	return &scoped
+}
+
+// UsesToken reports whether the client authenticates with a bearer token.
+func (c *Client) UsesToken() bool {
+	return c.token != ""
+}
+
+// OrganizationId returns the id of the organization the client is scoped to, 0 if it is not scoped.
+func (c *Client) OrganizationId() int64 {
+	return c.orgId
+}
+
+func (c *Client) newRequest(ctx context.Context, method, slug string, body io.Reader) (*http.Request, error) {
+	if c.token != "" {
+		for _, prefix := range serverAdminSlugs {
+			if strings.HasPrefix(slug, prefix) {
+				return nil, newError(ErrPermission, "Server admin API "+method+" "+slug+" is not available with token authentication, LOGIN and PASSWORD are required")
+			}
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+slug, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else {
+		req.SetBasicAuth(c.login, c.password)
+	}
+	if c.orgId > 0 {
+		req.Header.Set("X-Grafana-Org-Id", strconv.FormatInt(c.orgId, 10))
+	}
+
+	return req, nil
 }
@@ -0,0 +1,231 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// TimeRange is a range of the day, start and end given as "15:04".
+type TimeRange struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// TimeInterval is matched when all of its set fields match, e.g. weekdays ["saturday:sunday"],
+// days of month ["1:7", "-1"], months ["1:3"], years ["2024"].
+type TimeInterval struct {
+	Times       []TimeRange `json:"times,omitempty"`
+	Weekdays    []string    `json:"weekdays,omitempty"`
+	DaysOfMonth []string    `json:"days_of_month,omitempty"`
+	Months      []string    `json:"months,omitempty"`
+	Years       []string    `json:"years,omitempty"`
+	Location    string      `json:"location,omitempty"`
+}
+
+// MuteTiming mutes the notifications of the notification policies referencing its Name in their
+// mute_time_intervals while any of its TimeIntervals matches.
+type MuteTiming struct {
+	Name          string         `json:"name"`
+	TimeIntervals []TimeInterval `json:"time_intervals"`
+	Version       string         `json:"version,omitempty"`
+	Provenance    string         `json:"provenance,omitempty"`
+}
+
+// GetMuteTimings returns all the mute timings of the organization the client is scoped to.
+func (c *Client) GetMuteTimings(ctx context.Context) ([]MuteTiming, error) {
+	slug := "/api/v1/provisioning/mute-timings"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		muteTimings := make([]MuteTiming, 0)
+		err = json.Unmarshal(body, &muteTimings)
+		if err != nil {
+			return nil, err
+		}
+
+		return muteTimings, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) GetMuteTiming(ctx context.Context, muteTiming *MuteTiming) (*MuteTiming, error) {
+	if muteTiming == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if muteTiming.Name == "" {
+		return nil, newError(ErrValidation, "No Name has been set for mute timing")
+	}
+
+	slug := "/api/v1/provisioning/mute-timings/" + url.PathEscape(muteTiming.Name)
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		err = json.Unmarshal(body, muteTiming)
+		if err != nil {
+			return nil, err
+		}
+
+		return muteTiming, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) CreateMuteTiming(ctx context.Context, muteTiming *MuteTiming) (*MuteTiming, error) {
+	if muteTiming == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/v1/provisioning/mute-timings"
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(muteTiming)
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
+	if err != nil {
+		return muteTiming, err
+	}
+
+	provisioningRequest(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return muteTiming, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return muteTiming, err
+	}
+
+	if res.StatusCode == 200 || res.StatusCode == 201 {
+		err = json.Unmarshal(body, muteTiming)
+		if err != nil {
+			return muteTiming, err
+		}
+
+		return muteTiming, nil
+	}
+
+	return muteTiming, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) UpdateMuteTiming(ctx context.Context, muteTiming *MuteTiming) (*MuteTiming, error) {
+	if muteTiming == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if muteTiming.Name == "" {
+		return nil, newError(ErrValidation, "No Name has been set for mute timing")
+	}
+
+	slug := "/api/v1/provisioning/mute-timings/" + url.PathEscape(muteTiming.Name)
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(muteTiming)
+
+	req, err := c.newRequest(ctx, http.MethodPut, slug, payloadBuffer)
+	if err != nil {
+		return muteTiming, err
+	}
+
+	provisioningRequest(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return muteTiming, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return muteTiming, err
+	}
+
+	if res.StatusCode == 200 || res.StatusCode == 202 {
+		err = json.Unmarshal(body, muteTiming)
+		if err != nil {
+			return muteTiming, err
+		}
+
+		return muteTiming, nil
+	}
+
+	return muteTiming, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) DeleteMuteTiming(ctx context.Context, muteTiming *MuteTiming) (bool, error) {
+	if muteTiming == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	} else if muteTiming.Name == "" {
+		return false, newError(ErrValidation, "No Name has been set for mute timing")
+	}
+
+	slug := "/api/v1/provisioning/mute-timings/" + url.PathEscape(muteTiming.Name)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
+	if err != nil {
+		return false, err
+	}
+
+	provisioningRequest(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 || res.StatusCode == 204 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
@@ -2,11 +2,14 @@ package apiv1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,6 +18,21 @@ type Organization struct {
 	Name string `json:"name"`
 }
 
+// Roles of a user in an organization
+const (
+	RoleAdmin  = "Admin"
+	RoleEditor = "Editor"
+	RoleViewer = "Viewer"
+	RoleNone   = "None"
+)
+
+// Changes of the members of an organization
+const (
+	MemberAdded   = "added"
+	MemberUpdated = "updated"
+	MemberRemoved = "removed"
+)
+
 type OrganizationUser struct {
 	Id         int64     `json:"userId"`
 	Email      string    `json:"email"`
@@ -25,19 +43,58 @@ type OrganizationUser struct {
 	LastSeenAt time.Time `json:"lastSeenAt"`
 }
 
-func GetOrganizations() ([]Organization, error) {
+// MemberChange is a change made to the members of an organization, Error is set when it failed.
+type MemberChange struct {
+	UserId int64  `json:"userId"`
+	Login  string `json:"login"`
+	Role   string `json:"role,omitempty"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// GetOrganizations returns all the organizations, walking every page of them.
+func (c *Client) GetOrganizations(ctx context.Context) ([]Organization, error) {
+	organizations := make([]Organization, 0)
+	err := c.WalkOrganizations(ctx, DefaultPerPage, func(page []Organization) error {
+		organizations = append(organizations, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return organizations, nil
+}
+
+// WalkOrganizations calls fn with every page of perPage organizations until fn returns an error.
+func (c *Client) WalkOrganizations(ctx context.Context, perPage int, fn func(organizations []Organization) error) error {
+	return walkPages(perPage, func(page Page) (int, int64, error) {
+		organizations, err := c.GetOrganizationsPage(ctx, page)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		return len(organizations), -1, fn(organizations)
+	})
+}
+
+func (c *Client) GetOrganizationsPage(ctx context.Context, page Page) ([]Organization, error) {
+	page = page.normalize()
 	slug := "/api/orgs"
-	url := grafanaClientSettings.url + slug
 
-	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	q := req.URL.Query()
+	q.Add("page", strconv.Itoa(page.Page))
+	q.Add("perpage", strconv.Itoa(page.PerPage))
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +107,7 @@ func GetOrganizations() ([]Organization, error) {
 	}
 
 	if res.StatusCode == 200 {
-		var organizations []Organization
+		organizations := make([]Organization, 0)
 		err = json.Unmarshal(body, &organizations)
 		if err != nil {
 			return nil, err
@@ -59,34 +116,31 @@ func GetOrganizations() ([]Organization, error) {
 		return organizations, nil
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
 }
 
-func GetOrganization(organization *Organization) (*Organization, error) {
+func (c *Client) GetOrganization(ctx context.Context, organization *Organization) (*Organization, error) {
 	slug := ""
 
 	if organization == nil {
-		return nil, errors.New("Nil pointer")
+		return nil, newError(ErrValidation, "Nil pointer")
 	} else if organization.Id > 0 {
 		slug = "/api/orgs/" + strconv.FormatInt(organization.Id, 10)
 	} else if organization.Name != "" {
 		slug = "/api/orgs/name/" + organization.Name
 	} else {
-		return nil, errors.New("No Id, Name has been set for organization")
+		return nil, newError(ErrValidation, "No Id, Name has been set for organization")
 	}
 
-	url := grafanaClientSettings.url + slug
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -106,29 +160,129 @@ func GetOrganization(organization *Organization) (*Organization, error) {
 
 		return organization, nil
 	} else if res.StatusCode == 404 {
-		return nil, errors.New("Empty result")
+		return nil, ErrNotFound
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
 }
 
-func SwitchCurrentOrganizationForUser(user *User, orgId int) (bool, error) {
+// GetCurrentOrganization returns the current organization of the authenticated user,
+// the organization an API key or a service account token belongs to.
+func (c *Client) GetCurrentOrganization(ctx context.Context) (*Organization, error) {
+	slug := "/api/org"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		var organization Organization
+		err = json.Unmarshal(body, &organization)
+		if err != nil {
+			return nil, err
+		}
+
+		return &organization, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// GetCurrentOrganizationUsers returns the members of the organization the client is scoped to
+// whose login, email or name matches the query, every member if it is empty. Unlike
+// GetUsersInOrganization it only needs an admin of the organization, or a token of it.
+func (c *Client) GetCurrentOrganizationUsers(ctx context.Context, query string) ([]OrganizationUser, error) {
+	slug := "/api/org/users"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	if query != "" {
+		q := req.URL.Query()
+		q.Add("query", query)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		users := make([]OrganizationUser, 0)
+		err = json.Unmarshal(body, &users)
+		if err != nil {
+			return nil, err
+		}
+
+		return users, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// LookupOrganizationUser finds the member of the organization the client is scoped to by
+// login or email, without the server admin user lookup.
+func (c *Client) LookupOrganizationUser(ctx context.Context, login, email string) (*OrganizationUser, error) {
+	query := login
+	if query == "" {
+		query = email
+	}
+
+	users, err := c.GetCurrentOrganizationUsers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	member := findOrganizationUser(users, OrganizationUser{Login: login, Email: email})
+	if member == nil {
+		return nil, newError(ErrNotFound, "No member of the organization with login "+login+" or email "+email)
+	}
+
+	return member, nil
+}
+
+func (c *Client) SwitchCurrentOrganizationForUser(ctx context.Context, user *User, orgId int) (bool, error) {
 	if user == nil {
-		return false, errors.New("Nil pointer")
+		return false, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/users/" + strconv.FormatInt(user.Id, 10) + "/using/" + strconv.Itoa(orgId)
-	url := grafanaClientSettings.url + slug
 
-	req, err := http.NewRequest(http.MethodPost, url, http.NoBody)
+	req, err := c.newRequest(ctx, http.MethodPost, slug, http.NoBody)
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -152,7 +306,7 @@ func SwitchCurrentOrganizationForUser(user *User, orgId int) (bool, error) {
 		}
 	}
 
-	return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return false, newResponseError(res.StatusCode, body)
 }
 
 //Note: The api will work in the following two ways
@@ -160,27 +314,25 @@ func SwitchCurrentOrganizationForUser(user *User, orgId int) (bool, error) {
 //Need to set GF_USERS_ALLOW_ORG_CREATE=true
 //Set the config value users.allow_org_create to true in ini file
 
-func CreateOrganization(organization *Organization) (*Organization, error) {
+func (c *Client) CreateOrganization(ctx context.Context, organization *Organization) (*Organization, error) {
 	if organization == nil {
-		return nil, errors.New("Nil pointer")
+		return nil, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/orgs"
-	url := grafanaClientSettings.url + slug
 
 	payloadBuffer := new(bytes.Buffer)
 	json.NewEncoder(payloadBuffer).Encode(organization)
 
-	req, err := http.NewRequest(http.MethodPost, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
 	if err != nil {
 		return organization, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return organization, err
 	}
@@ -206,30 +358,28 @@ func CreateOrganization(organization *Organization) (*Organization, error) {
 		return organization, nil
 	}
 
-	return organization, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return organization, newResponseError(res.StatusCode, body)
 }
 
-func UpdateOrganization(organization *Organization) (bool, error) {
+func (c *Client) UpdateOrganization(ctx context.Context, organization *Organization) (bool, error) {
 	if organization == nil {
-		return false, errors.New("Nil pointer")
+		return false, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/orgs/" + strconv.FormatInt(organization.Id, 10)
-	url := grafanaClientSettings.url + slug
 
 	payloadBuffer := new(bytes.Buffer)
 	json.NewEncoder(payloadBuffer).Encode(organization)
 
-	req, err := http.NewRequest(http.MethodPut, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodPut, slug, payloadBuffer)
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -253,27 +403,25 @@ func UpdateOrganization(organization *Organization) (bool, error) {
 		}
 	}
 
-	return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return false, newResponseError(res.StatusCode, body)
 }
 
-func DeleteOrganization(organization *Organization) (bool, error) {
+func (c *Client) DeleteOrganization(ctx context.Context, organization *Organization) (bool, error) {
 	if organization == nil {
-		return false, errors.New("Nil pointer")
+		return false, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/orgs/" + strconv.FormatInt(organization.Id, 10)
-	url := grafanaClientSettings.url + slug
 
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -297,30 +445,28 @@ func DeleteOrganization(organization *Organization) (bool, error) {
 		}
 	}
 
-	return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return false, newResponseError(res.StatusCode, body)
 }
 
-func GetUsersInOrganization(organization *Organization) (*[]OrganizationUser, error) {
+func (c *Client) GetUsersInOrganization(ctx context.Context, organization *Organization) (*[]OrganizationUser, error) {
 	if organization == nil {
-		return nil, errors.New("Nil pointer")
+		return nil, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/orgs/" + strconv.FormatInt(organization.Id, 10) + "/users"
-	url := grafanaClientSettings.url + slug
 
 	payloadBuffer := new(bytes.Buffer)
 	json.NewEncoder(payloadBuffer).Encode(organization)
 
-	req, err := http.NewRequest(http.MethodGet, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, payloadBuffer)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -342,5 +488,121 @@ func GetUsersInOrganization(organization *Organization) (*[]OrganizationUser, er
 		return &users, nil
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// OrganizationUsersOptions tunes the way SetOrganizationUsersWith replaces the members of an organization.
+type OrganizationUsersOptions struct {
+	// AllowEmpty lets an empty list remove every member, it is refused with ErrValidation otherwise.
+	AllowEmpty bool
+}
+
+// SetOrganizationUsers makes the users the only members of the organization, see SetOrganizationUsersWith.
+func (c *Client) SetOrganizationUsers(ctx context.Context, organization *Organization, users []OrganizationUser) ([]MemberChange, error) {
+	return c.SetOrganizationUsersWith(ctx, organization, users, OrganizationUsersOptions{})
+}
+
+// SetOrganizationUsersWith makes the users, found by Id, Login or Email, the only members of the
+// organization: the missing ones are added, the ones with another Role updated (Viewer by
+// default) and the others removed. Members are added and promoted before the others are
+// demoted and removed so the organization keeps an admin along the way. A failed change does
+// not stop the following ones, it is reported in the returned changes. An empty list is
+// refused unless the options allow it.
+func (c *Client) SetOrganizationUsersWith(ctx context.Context, organization *Organization, users []OrganizationUser, options OrganizationUsersOptions) ([]MemberChange, error) {
+	if organization == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	if len(users) == 0 && !options.AllowEmpty {
+		return nil, newError(ErrValidation, "Empty user list")
+	}
+
+	members, err := c.GetUsersInOrganization(ctx, organization)
+	if err != nil {
+		return nil, err
+	}
+
+	// the users are matched with the members before any change is made
+	wanted := make([]OrganizationUser, 0, len(users))
+	kept := make(map[int64]bool)
+	for _, user := range users {
+		if user.Role == "" {
+			user.Role = RoleViewer
+		}
+		switch user.Role {
+		case RoleAdmin, RoleEditor, RoleViewer, RoleNone:
+		default:
+			return nil, newError(ErrValidation, "Unknown role "+user.Role)
+		}
+
+		member := findOrganizationUser(*members, user)
+		if member == nil {
+			if user.Id == 0 && user.Login == "" && user.Email == "" {
+				return nil, newError(ErrValidation, "No Id, Login, Email has been set for user")
+			}
+			found := User{Id: user.Id, Login: user.Login, Email: user.Email}
+			_, err = c.GetUser(ctx, &found)
+			if errors.Is(err, ErrNotFound) {
+				return nil, newError(ErrNotFound, "User "+user.Login+user.Email+" doesn't exist")
+			} else if err != nil {
+				return nil, err
+			}
+			user.Id, user.Login, user.Email = found.Id, found.Login, found.Email
+		} else {
+			user.Id, user.Login, user.Email = member.Id, member.Login, member.Email
+		}
+		if kept[user.Id] {
+			return nil, newError(ErrValidation, "User "+user.Login+" is listed twice")
+		}
+		kept[user.Id] = true
+		wanted = append(wanted, user)
+	}
+
+	changes := make([]MemberChange, 0)
+	change := func(user OrganizationUser, action string, err error) {
+		memberChange := MemberChange{UserId: user.Id, Login: user.Login, Role: user.Role, Action: action}
+		if err != nil {
+			c.logger.Printf("Got error: %v\n", err.Error()+" ("+user.Login+")")
+			memberChange.Error = err.Error()
+		}
+		changes = append(changes, memberChange)
+	}
+
+	// the admins first
+	sort.SliceStable(wanted, func(i, j int) bool {
+		return wanted[i].Role == RoleAdmin && wanted[j].Role != RoleAdmin
+	})
+	for _, user := range wanted {
+		member := findOrganizationUser(*members, user)
+		if member == nil {
+			_, err = c.AddUserToOrganization(ctx, &User{Id: user.Id, Login: user.Login, Email: user.Email}, organization, user.Role)
+			change(user, MemberAdded, err)
+		} else if member.Role != user.Role {
+			_, err = c.UpdateUserInOrganization(ctx, &User{Id: user.Id}, organization, user.Role)
+			change(user, MemberUpdated, err)
+		}
+	}
+
+	for _, member := range *members {
+		if kept[member.Id] {
+			continue
+		}
+		_, err = c.DeleteUserFromOrganization(ctx, &User{Id: member.Id}, organization)
+		change(OrganizationUser{Id: member.Id, Login: member.Login}, MemberRemoved, err)
+	}
+
+	return changes, nil
+}
+
+// findOrganizationUser returns the member matching the user by Id, Login or Email, nil if there is none.
+func findOrganizationUser(members []OrganizationUser, user OrganizationUser) *OrganizationUser {
+	for i, member := range members {
+		if (user.Id > 0 && member.Id == user.Id) ||
+			(user.Login != "" && strings.EqualFold(member.Login, user.Login)) ||
+			(user.Email != "" && strings.EqualFold(member.Email, user.Email)) {
+			return &members[i]
+		}
+	}
+
+	return nil
 }
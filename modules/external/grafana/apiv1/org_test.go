@@ -0,0 +1,19 @@
+package apiv1
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSetOrganizationUsersRefusesEmptyList(t *testing.T) {
+	// the list is checked before Grafana is called
+	client := NewClient("http://grafana.invalid", "admin", "admin")
+
+	for _, users := range [][]OrganizationUser{nil, {}} {
+		_, err := client.SetOrganizationUsers(context.Background(), &Organization{Id: 3}, users)
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("SetOrganizationUsers(%v): got %v, want ErrValidation", users, err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package apiv1
+
+// DefaultPerPage is the page size used to walk the paged Grafana APIs when none is given.
+const DefaultPerPage = 1000
+
+// Page selects a page of a paged Grafana API, pages are numbered from 1.
+type Page struct {
+	Page    int
+	PerPage int
+}
+
+func (p Page) normalize() Page {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.PerPage < 1 {
+		p.PerPage = DefaultPerPage
+	}
+
+	return p
+}
+
+// walkPages fetches the pages 1, 2, ... of perPage items until one comes back short, or until
+// all the items have been fetched when Grafana tells their total count (total >= 0).
+func walkPages(perPage int, fetch func(page Page) (count int, total int64, err error)) error {
+	if perPage < 1 {
+		perPage = DefaultPerPage
+	}
+
+	var fetched int64
+	for page := 1; ; page++ {
+		count, total, err := fetch(Page{Page: page, PerPage: perPage})
+		if err != nil {
+			return err
+		}
+
+		fetched += int64(count)
+		if count == 0 || count < perPage || (total >= 0 && fetched >= total) {
+			return nil
+		}
+	}
+}
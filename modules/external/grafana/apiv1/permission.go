@@ -0,0 +1,208 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Permission levels of a folder or dashboard permission
+const (
+	PermissionView  = 1
+	PermissionEdit  = 2
+	PermissionAdmin = 4
+)
+
+var permissionLevels = map[string]int{
+	"View":  PermissionView,
+	"Edit":  PermissionEdit,
+	"Admin": PermissionAdmin,
+}
+
+// Permission grants a permission level on a folder or dashboard to a single user, team or role
+// (Viewer, Editor). The user could be given by UserLogin or UserEmail, the team by Team (its
+// name) and the level by PermissionName (View, Edit, Admin), they are resolved on update.
+type Permission struct {
+	UserId         int64  `json:"userId,omitempty"`
+	UserLogin      string `json:"userLogin,omitempty"`
+	UserEmail      string `json:"userEmail,omitempty"`
+	TeamId         int64  `json:"teamId,omitempty"`
+	Team           string `json:"team,omitempty"`
+	Role           string `json:"role,omitempty"`
+	Permission     int    `json:"permission"`
+	PermissionName string `json:"permissionName,omitempty"`
+	Inherited      bool   `json:"inherited,omitempty"`
+}
+
+// GetFolderPermissions returns the effective ACL of the folder.
+func (c *Client) GetFolderPermissions(ctx context.Context, folder *Folder) ([]Permission, error) {
+	if folder == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	return c.getPermissions(ctx, "/api/folders/"+folder.Uid+"/permissions")
+}
+
+// SetFolderPermissions replaces the ACL of the folder with the permissions at once.
+func (c *Client) SetFolderPermissions(ctx context.Context, folder *Folder, permissions *[]Permission) (bool, error) {
+	if folder == nil || permissions == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	}
+
+	return c.setPermissions(ctx, "/api/folders/"+folder.Uid+"/permissions", permissions)
+}
+
+// GetDashboardPermissions returns the effective ACL of the dashboard, the permissions
+// inherited from its folder included.
+func (c *Client) GetDashboardPermissions(ctx context.Context, dashboard *Dashboard) ([]Permission, error) {
+	if dashboard == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	return c.getPermissions(ctx, "/api/dashboards/uid/"+dashboard.Dashboard.Uid+"/permissions")
+}
+
+// SetDashboardPermissions replaces the ACL of the dashboard with the permissions at once,
+// the permissions inherited from its folder are kept.
+func (c *Client) SetDashboardPermissions(ctx context.Context, dashboard *Dashboard, permissions *[]Permission) (bool, error) {
+	if dashboard == nil || permissions == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	}
+
+	return c.setPermissions(ctx, "/api/dashboards/uid/"+dashboard.Dashboard.Uid+"/permissions", permissions)
+}
+
+func (c *Client) getPermissions(ctx context.Context, slug string) ([]Permission, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		permissions := make([]Permission, 0)
+		err = json.Unmarshal(body, &permissions)
+		if err != nil {
+			return nil, err
+		}
+
+		return permissions, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) setPermissions(ctx context.Context, slug string, permissions *[]Permission) (bool, error) {
+	// every grant is resolved before the ACL is replaced, so it is left untouched on failure
+	type item struct {
+		UserId     int64  `json:"userId,omitempty"`
+		TeamId     int64  `json:"teamId,omitempty"`
+		Role       string `json:"role,omitempty"`
+		Permission int    `json:"permission"`
+	}
+	items := make([]item, 0, len(*permissions))
+	for i := range *permissions {
+		permission := &(*permissions)[i]
+		if permission.Inherited {
+			continue
+		}
+
+		err := c.resolvePermission(ctx, permission)
+		if err != nil {
+			return false, err
+		}
+		items = append(items, item{permission.UserId, permission.TeamId, permission.Role, permission.Permission})
+	}
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(map[string]interface{}{
+		"items": items,
+	})
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
+
+// resolvePermission sets the UserId, TeamId and Permission of a permission given by names.
+func (c *Client) resolvePermission(ctx context.Context, permission *Permission) error {
+	if permission.Permission == 0 && permission.PermissionName != "" {
+		level, ok := permissionLevels[permission.PermissionName]
+		if !ok {
+			return newError(ErrValidation, "Unknown permission "+permission.PermissionName+", expected View, Edit or Admin")
+		}
+		permission.Permission = level
+	}
+	if permission.Permission != PermissionView && permission.Permission != PermissionEdit && permission.Permission != PermissionAdmin {
+		return newError(ErrValidation, "Unknown permission "+strconv.Itoa(permission.Permission)+", expected 1, 2 or 4")
+	}
+
+	if permission.UserId == 0 && (permission.UserLogin != "" || permission.UserEmail != "") {
+		// users are looked up among the members of the organization, the user lookup needs the server admin
+		user, err := c.LookupOrganizationUser(ctx, permission.UserLogin, permission.UserEmail)
+		if err != nil {
+			return err
+		}
+		permission.UserId = user.Id
+	}
+
+	// teams are searched by name in the organization the client is scoped to
+	if permission.TeamId == 0 && permission.Team != "" {
+		team := Team{Name: permission.Team}
+		_, err := c.GetTeam(ctx, &team)
+		if err != nil {
+			return err
+		}
+		permission.TeamId = team.Id
+	}
+
+	grantees := 0
+	for _, set := range []bool{permission.UserId > 0, permission.TeamId > 0, permission.Role != ""} {
+		if set {
+			grantees++
+		}
+	}
+	if grantees != 1 {
+		return newError(ErrValidation, "A permission is granted to exactly one user, team or role")
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// NotificationPolicy is a node of the notification policy tree of an organization. The alerts
+// matching its ObjectMatchers (["label", "=", "value"] triples) are sent to the contact points
+// named Receiver, or handled by its child Routes first. The root policy matches every alert.
+type NotificationPolicy struct {
+	Receiver            string               `json:"receiver,omitempty"`
+	GroupBy             []string             `json:"group_by,omitempty"`
+	ObjectMatchers      [][]string           `json:"object_matchers,omitempty"`
+	Matchers            []string             `json:"matchers,omitempty"`
+	MuteTimeIntervals   []string             `json:"mute_time_intervals,omitempty"`
+	ActiveTimeIntervals []string             `json:"active_time_intervals,omitempty"`
+	Continue            bool                 `json:"continue,omitempty"`
+	GroupWait           string               `json:"group_wait,omitempty"`
+	GroupInterval       string               `json:"group_interval,omitempty"`
+	RepeatInterval      string               `json:"repeat_interval,omitempty"`
+	Routes              []NotificationPolicy `json:"routes,omitempty"`
+	Provenance          string               `json:"provenance,omitempty"`
+}
+
+// receivers adds the receivers of the policy and of all its descendants to names.
+func (p *NotificationPolicy) receivers(names map[string]bool) {
+	if p.Receiver != "" {
+		names[p.Receiver] = true
+	}
+	for i := range p.Routes {
+		p.Routes[i].receivers(names)
+	}
+}
+
+// GetNotificationPolicyTree returns the whole notification policy tree of the organization the client is scoped to.
+func (c *Client) GetNotificationPolicyTree(ctx context.Context) (*NotificationPolicy, error) {
+	slug := "/api/v1/provisioning/policies"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		policy := &NotificationPolicy{}
+		err = json.Unmarshal(body, policy)
+		if err != nil {
+			return nil, err
+		}
+
+		return policy, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// SetNotificationPolicyTree replaces the whole notification policy tree with the policy. Every
+// receiver of the tree is checked to be the name of a contact point of the organization first,
+// the tree is left untouched otherwise.
+func (c *Client) SetNotificationPolicyTree(ctx context.Context, policy *NotificationPolicy) (bool, error) {
+	if policy == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	} else if policy.Receiver == "" {
+		return false, newError(ErrValidation, "No Receiver has been set for the root notification policy")
+	}
+
+	contactPoints, err := c.GetContactPoints(ctx, "")
+	if err != nil {
+		return false, err
+	}
+	existing := make(map[string]bool, len(contactPoints))
+	for _, contactPoint := range contactPoints {
+		existing[contactPoint.Name] = true
+	}
+
+	receivers := make(map[string]bool)
+	policy.receivers(receivers)
+	unknown := make([]string, 0)
+	for receiver := range receivers {
+		if !existing[receiver] {
+			unknown = append(unknown, receiver)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return false, newError(ErrValidation, "No contact point is named "+strings.Join(unknown, ", "))
+	}
+
+	slug := "/api/v1/provisioning/policies"
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(policy)
+
+	req, err := c.newRequest(ctx, http.MethodPut, slug, payloadBuffer)
+	if err != nil {
+		return false, err
+	}
+
+	provisioningRequest(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 || res.StatusCode == 202 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
@@ -0,0 +1,135 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// SearchHit is a dashboard found by the Grafana search, along with its folder.
+type SearchHit struct {
+	Id          int64    `json:"id"`
+	Uid         string   `json:"uid"`
+	Title       string   `json:"title"`
+	Url         string   `json:"url"`
+	Type        string   `json:"type"`
+	Tags        []string `json:"tags"`
+	IsStarred   bool     `json:"isStarred"`
+	FolderId    int64    `json:"folderId,omitempty"`
+	FolderUid   string   `json:"folderUid,omitempty"`
+	FolderTitle string   `json:"folderTitle,omitempty"`
+	FolderUrl   string   `json:"folderUrl,omitempty"`
+}
+
+// Dashboard returns the dashboard summary of the hit, its dashboard JSON only has the
+// id, uid, title and tags.
+func (h SearchHit) Dashboard() Dashboard {
+	return Dashboard{
+		Dashboard: DashboardModel{Id: h.Id, Uid: h.Uid, Title: h.Title, Tags: h.Tags},
+		Meta: DashboardMeta{
+			FolderId:    h.FolderId,
+			FolderUid:   h.FolderUid,
+			FolderTitle: h.FolderTitle,
+			Url:         h.Url,
+			IsStarred:   h.IsStarred,
+		},
+		FolderId:  h.FolderId,
+		FolderUid: h.FolderUid,
+	}
+}
+
+// DashboardSearch filters the dashboards, every set field has to match: Query is matched
+// against the title, a dashboard has all the Tags and is in any of the FolderUids. Sort is
+// a Grafana sort option (alpha-asc, alpha-desc, ...), by title by default.
+type DashboardSearch struct {
+	Query      string
+	Tags       []string
+	FolderUids []string
+	Starred    bool
+	Sort       string
+}
+
+// SearchDashboards returns all the dashboards matching the search, walking every page of them.
+func (c *Client) SearchDashboards(ctx context.Context, search DashboardSearch) ([]SearchHit, error) {
+	hits := make([]SearchHit, 0)
+	err := c.WalkDashboardSearch(ctx, search, DefaultPerPage, func(page []SearchHit) error {
+		hits = append(hits, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hits, nil
+}
+
+// WalkDashboardSearch calls fn with every page of perPage dashboards matching the search until fn returns an error.
+func (c *Client) WalkDashboardSearch(ctx context.Context, search DashboardSearch, perPage int, fn func(hits []SearchHit) error) error {
+	return walkPages(perPage, func(page Page) (int, int64, error) {
+		hits, err := c.SearchDashboardsPage(ctx, search, page)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		return len(hits), -1, fn(hits)
+	})
+}
+
+func (c *Client) SearchDashboardsPage(ctx context.Context, search DashboardSearch, page Page) ([]SearchHit, error) {
+	page = page.normalize()
+	slug := "/api/search/"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	q := req.URL.Query()
+	q.Add("type", "dash-db")
+	if search.Query != "" {
+		q.Add("query", search.Query)
+	}
+	for _, tag := range search.Tags {
+		q.Add("tag", tag)
+	}
+	for _, folderUid := range search.FolderUids {
+		q.Add("folderUIDs", folderUid)
+	}
+	if search.Starred {
+		q.Add("starred", "true")
+	}
+	if search.Sort != "" {
+		q.Add("sort", search.Sort)
+	}
+	q.Add("page", strconv.Itoa(page.Page))
+	q.Add("limit", strconv.Itoa(page.PerPage))
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		hits := make([]SearchHit, 0)
+		err = json.Unmarshal(body, &hits)
+		if err != nil {
+			return nil, err
+		}
+
+		return hits, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
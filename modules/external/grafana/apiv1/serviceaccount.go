@@ -0,0 +1,363 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ServiceAccount struct {
+	Id         int64  `json:"id,omitempty"`
+	Name       string `json:"name"`
+	Login      string `json:"login,omitempty"`
+	OrgId      int64  `json:"orgId,omitempty"`
+	Role       string `json:"role"`
+	IsDisabled bool   `json:"isDisabled"`
+	Tokens     int64  `json:"tokens"`
+}
+
+// ServiceAccountToken is a token of a service account. Key, the secret of the token,
+// is only returned by Grafana once, on creation.
+type ServiceAccountToken struct {
+	Id                     int64      `json:"id,omitempty"`
+	Name                   string     `json:"name"`
+	SecondsToLive          int64      `json:"secondsToLive,omitempty"`
+	Created                *time.Time `json:"created,omitempty"`
+	Expiration             *time.Time `json:"expiration,omitempty"`
+	SecondsUntilExpiration float64    `json:"secondsUntilExpiration,omitempty"`
+	HasExpired             bool       `json:"hasExpired"`
+	LastUsedAt             *time.Time `json:"lastUsedAt,omitempty"`
+	Key                    string     `json:"key,omitempty"`
+}
+
+// GetServiceAccounts returns the service accounts of the organization the client is scoped to,
+// walking every page of them.
+func (c *Client) GetServiceAccounts(ctx context.Context) ([]ServiceAccount, error) {
+	serviceAccounts := make([]ServiceAccount, 0)
+	err := c.WalkServiceAccounts(ctx, DefaultPerPage, func(page []ServiceAccount) error {
+		serviceAccounts = append(serviceAccounts, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return serviceAccounts, nil
+}
+
+// WalkServiceAccounts calls fn with every page of perPage service accounts until fn returns an error.
+func (c *Client) WalkServiceAccounts(ctx context.Context, perPage int, fn func(serviceAccounts []ServiceAccount) error) error {
+	return walkPages(perPage, func(page Page) (int, int64, error) {
+		serviceAccounts, totalCount, err := c.GetServiceAccountsPage(ctx, page)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		return len(serviceAccounts), totalCount, fn(serviceAccounts)
+	})
+}
+
+// GetServiceAccountsPage returns a page of the service accounts along with the count of all of them.
+func (c *Client) GetServiceAccountsPage(ctx context.Context, page Page) ([]ServiceAccount, int64, error) {
+	page = page.normalize()
+	slug := "/api/serviceaccounts/search"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	q := req.URL.Query()
+	q.Add("page", strconv.Itoa(page.Page))
+	q.Add("perpage", strconv.Itoa(page.PerPage))
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if res.StatusCode == 200 {
+		data := struct {
+			TotalCount      int64            `json:"totalCount"`
+			ServiceAccounts []ServiceAccount `json:"serviceAccounts"`
+		}{ServiceAccounts: make([]ServiceAccount, 0)}
+		err = json.Unmarshal(body, &data)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return data.ServiceAccounts, data.TotalCount, nil
+	}
+
+	return nil, 0, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) GetServiceAccount(ctx context.Context, serviceAccount *ServiceAccount) (*ServiceAccount, error) {
+	if serviceAccount == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if serviceAccount.Id == 0 {
+		return nil, newError(ErrValidation, "No Id has been set for service account")
+	}
+
+	slug := "/api/serviceaccounts/" + strconv.FormatInt(serviceAccount.Id, 10)
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		err = json.Unmarshal(body, serviceAccount)
+		if err != nil {
+			return nil, err
+		}
+
+		return serviceAccount, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) CreateServiceAccount(ctx context.Context, serviceAccount *ServiceAccount) (*ServiceAccount, error) {
+	if serviceAccount == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/serviceaccounts"
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(struct {
+		Name       string `json:"name"`
+		Role       string `json:"role,omitempty"`
+		IsDisabled bool   `json:"isDisabled"`
+	}{serviceAccount.Name, serviceAccount.Role, serviceAccount.IsDisabled})
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
+	if err != nil {
+		return serviceAccount, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return serviceAccount, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return serviceAccount, err
+	}
+
+	if res.StatusCode == 200 || res.StatusCode == 201 {
+		err = json.Unmarshal(body, serviceAccount)
+		if err != nil {
+			return serviceAccount, err
+		}
+
+		return serviceAccount, nil
+	}
+
+	return serviceAccount, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) DeleteServiceAccount(ctx context.Context, serviceAccount *ServiceAccount) (bool, error) {
+	if serviceAccount == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/serviceaccounts/" + strconv.FormatInt(serviceAccount.Id, 10)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
+
+// GetServiceAccountTokens returns the tokens of the service account, without their keys.
+func (c *Client) GetServiceAccountTokens(ctx context.Context, serviceAccount *ServiceAccount) ([]ServiceAccountToken, error) {
+	if serviceAccount == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/serviceaccounts/" + strconv.FormatInt(serviceAccount.Id, 10) + "/tokens"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		var tokens []ServiceAccountToken
+		err = json.Unmarshal(body, &tokens)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range tokens {
+			tokens[i].Key = ""
+		}
+
+		return tokens, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// CreateServiceAccountToken creates the token expiring after token.SecondsToLive seconds
+// (never if 0) and sets its Id and Key, the secret which cannot be retrieved afterwards.
+func (c *Client) CreateServiceAccountToken(ctx context.Context, serviceAccount *ServiceAccount, token *ServiceAccountToken) (*ServiceAccountToken, error) {
+	if serviceAccount == nil || token == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/serviceaccounts/" + strconv.FormatInt(serviceAccount.Id, 10) + "/tokens"
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(struct {
+		Name          string `json:"name"`
+		SecondsToLive int64  `json:"secondsToLive,omitempty"`
+	}{token.Name, token.SecondsToLive})
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
+	if err != nil {
+		return token, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return token, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return token, err
+	}
+
+	if res.StatusCode == 200 {
+		var data struct {
+			Id   int64  `json:"id"`
+			Name string `json:"name"`
+			Key  string `json:"key"`
+		}
+		err = json.Unmarshal(body, &data)
+		if err != nil {
+			return token, err
+		}
+
+		token.Id = data.Id
+		token.Name = data.Name
+		token.Key = data.Key
+		if token.SecondsToLive > 0 {
+			expiration := time.Now().Add(time.Duration(token.SecondsToLive) * time.Second).UTC().Truncate(time.Second)
+			token.Expiration = &expiration
+		}
+
+		return token, nil
+	}
+
+	return token, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) DeleteServiceAccountToken(ctx context.Context, serviceAccount *ServiceAccount, token *ServiceAccountToken) (bool, error) {
+	if serviceAccount == nil || token == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/serviceaccounts/" + strconv.FormatInt(serviceAccount.Id, 10) + "/tokens/" + strconv.FormatInt(token.Id, 10)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
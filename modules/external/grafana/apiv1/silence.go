@@ -0,0 +1,212 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// States of a silence
+const (
+	SilenceActive  = "active"
+	SilencePending = "pending"
+	SilenceExpired = "expired"
+)
+
+// SilenceMatcher selects the alerts of a silence by a label, IsEqual false negates the match.
+type SilenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// Silence mutes the notifications of the alerts matching all its Matchers from StartsAt to EndsAt
+// in the Grafana alertmanager of an organization.
+type Silence struct {
+	Id        string           `json:"id,omitempty"`
+	Matchers  []SilenceMatcher `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+	Status    *SilenceStatus   `json:"status,omitempty"`
+}
+
+type SilenceStatus struct {
+	State string `json:"state"`
+}
+
+// State returns the state of the silence told by Grafana, empty for a silence not created yet.
+func (s *Silence) State() string {
+	if s.Status == nil {
+		return ""
+	}
+
+	return s.Status.State
+}
+
+// GetSilences returns all the silences, expired ones included, of the organization the client is scoped to.
+func (c *Client) GetSilences(ctx context.Context) ([]Silence, error) {
+	slug := "/api/alertmanager/grafana/api/v2/silences"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		silences := make([]Silence, 0)
+		err = json.Unmarshal(body, &silences)
+		if err != nil {
+			return nil, err
+		}
+
+		return silences, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) GetSilence(ctx context.Context, silence *Silence) (*Silence, error) {
+	if silence == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if silence.Id == "" {
+		return nil, newError(ErrValidation, "No Id has been set for silence")
+	}
+
+	slug := "/api/alertmanager/grafana/api/v2/silence/" + url.PathEscape(silence.Id)
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		err = json.Unmarshal(body, silence)
+		if err != nil {
+			return nil, err
+		}
+
+		return silence, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// CreateSilence creates the silence and sets its Id, a silence with an Id is updated instead.
+func (c *Client) CreateSilence(ctx context.Context, silence *Silence) (*Silence, error) {
+	if silence == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if len(silence.Matchers) == 0 {
+		return silence, newError(ErrValidation, "No Matchers have been set for silence")
+	} else if !silence.EndsAt.After(silence.StartsAt) {
+		return silence, newError(ErrValidation, "The silence must end after it starts")
+	}
+
+	slug := "/api/alertmanager/grafana/api/v2/silences"
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(silence)
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
+	if err != nil {
+		return silence, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return silence, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return silence, err
+	}
+
+	if res.StatusCode == 200 || res.StatusCode == 202 {
+		var data struct {
+			SilenceId string `json:"silenceID"`
+		}
+		err = json.Unmarshal(body, &data)
+		if err != nil {
+			return silence, err
+		}
+		silence.Id = data.SilenceId
+
+		return silence, nil
+	}
+
+	return silence, newResponseError(res.StatusCode, body)
+}
+
+// ExpireSilence ends the silence at once, Grafana keeps it among the expired silences.
+func (c *Client) ExpireSilence(ctx context.Context, silence *Silence) (bool, error) {
+	if silence == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	} else if silence.Id == "" {
+		return false, newError(ErrValidation, "No Id has been set for silence")
+	}
+
+	slug := "/api/alertmanager/grafana/api/v2/silence/" + url.PathEscape(silence.Id)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
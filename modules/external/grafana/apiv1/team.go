@@ -0,0 +1,530 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// TeamPermissionAdmin is the permission of the team admins, plain members have 0.
+const TeamPermissionAdmin = 4
+
+type Team struct {
+	Id          int64  `json:"id,omitempty"`
+	OrgId       int64  `json:"orgId,omitempty"`
+	Name        string `json:"name"`
+	Email       string `json:"email,omitempty"`
+	AvatarUrl   string `json:"avatarUrl,omitempty"`
+	MemberCount int64  `json:"memberCount"`
+}
+
+type TeamMember struct {
+	UserId     int64  `json:"userId"`
+	TeamId     int64  `json:"teamId,omitempty"`
+	Email      string `json:"email,omitempty"`
+	Login      string `json:"login,omitempty"`
+	AvatarUrl  string `json:"avatarUrl,omitempty"`
+	Permission int    `json:"permission"`
+}
+
+// SearchTeams returns all the teams matching the query (every team if it is empty)
+// of the organization the client is scoped to, walking every page of them.
+func (c *Client) SearchTeams(ctx context.Context, query string) ([]Team, error) {
+	teams := make([]Team, 0)
+	err := c.WalkTeams(ctx, query, DefaultPerPage, func(page []Team) error {
+		teams = append(teams, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// WalkTeams calls fn with every page of perPage teams matching the query until fn returns an error.
+func (c *Client) WalkTeams(ctx context.Context, query string, perPage int, fn func(teams []Team) error) error {
+	return walkPages(perPage, func(page Page) (int, int64, error) {
+		teams, totalCount, err := c.SearchTeamsPage(ctx, query, page)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		return len(teams), totalCount, fn(teams)
+	})
+}
+
+// SearchTeamsPage returns a page of the teams matching the query along with the count of all of them.
+func (c *Client) SearchTeamsPage(ctx context.Context, query string, page Page) ([]Team, int64, error) {
+	page = page.normalize()
+	slug := "/api/teams/search"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	q := req.URL.Query()
+	q.Add("query", query)
+	q.Add("page", strconv.Itoa(page.Page))
+	q.Add("perpage", strconv.Itoa(page.PerPage))
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if res.StatusCode == 200 {
+		data := struct {
+			TotalCount int64  `json:"totalCount"`
+			Teams      []Team `json:"teams"`
+		}{Teams: make([]Team, 0)}
+		err = json.Unmarshal(body, &data)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return data.Teams, data.TotalCount, nil
+	}
+
+	return nil, 0, newResponseError(res.StatusCode, body)
+}
+
+// GetTeam fills the team by its Id, or by its exact Name when Id is not set.
+func (c *Client) GetTeam(ctx context.Context, team *Team) (*Team, error) {
+	slug := ""
+
+	if team == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if team.Id > 0 {
+		slug = "/api/teams/" + strconv.FormatInt(team.Id, 10)
+	} else if team.Name != "" {
+		slug = "/api/teams/search"
+	} else {
+		return nil, newError(ErrValidation, "No Id, Name has been set for team")
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	if team.Id == 0 {
+		q := req.URL.Query()
+		q.Add("name", team.Name)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		if team.Id > 0 {
+			err = json.Unmarshal(body, team)
+			if err != nil {
+				return nil, err
+			}
+
+			return team, nil
+		}
+
+		var data struct {
+			Teams []Team `json:"teams"`
+		}
+		err = json.Unmarshal(body, &data)
+		if err != nil {
+			return nil, err
+		}
+		if len(data.Teams) != 1 {
+			return nil, ErrNotFound
+		}
+
+		*team = data.Teams[0]
+		return team, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) CreateTeam(ctx context.Context, team *Team) (*Team, error) {
+	if team == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/teams"
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(map[string]string{
+		"name":  team.Name,
+		"email": team.Email,
+	})
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
+	if err != nil {
+		return team, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return team, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return team, err
+	}
+
+	if res.StatusCode == 200 {
+		var data struct {
+			TeamId int64 `json:"teamId"`
+		}
+		err = json.Unmarshal(body, &data)
+		if err != nil {
+			return team, err
+		}
+
+		team.Id = data.TeamId
+		return team, nil
+	}
+
+	return team, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) UpdateTeam(ctx context.Context, team *Team) (bool, error) {
+	if team == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/teams/" + strconv.FormatInt(team.Id, 10)
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(map[string]string{
+		"name":  team.Name,
+		"email": team.Email,
+	})
+
+	req, err := c.newRequest(ctx, http.MethodPut, slug, payloadBuffer)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) DeleteTeam(ctx context.Context, team *Team) (bool, error) {
+	if team == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/teams/" + strconv.FormatInt(team.Id, 10)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) GetTeamMembers(ctx context.Context, team *Team) ([]TeamMember, error) {
+	if team == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/teams/" + strconv.FormatInt(team.Id, 10) + "/members"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		members := make([]TeamMember, 0)
+		err = json.Unmarshal(body, &members)
+		if err != nil {
+			return nil, err
+		}
+
+		return members, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// AddTeamMember adds the user to the team, as a team admin if member.Permission is TeamPermissionAdmin.
+func (c *Client) AddTeamMember(ctx context.Context, team *Team, member *TeamMember) (bool, error) {
+	if team == nil || member == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	}
+
+	err := c.resolveTeamMember(ctx, member)
+	if err != nil {
+		return false, err
+	}
+
+	slug := "/api/teams/" + strconv.FormatInt(team.Id, 10) + "/members"
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(map[string]int64{
+		"userId": member.UserId,
+	})
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode != 200 {
+		return false, newResponseError(res.StatusCode, body)
+	}
+
+	if member.Permission != 0 {
+		return c.UpdateTeamMember(ctx, team, member)
+	}
+
+	return true, nil
+}
+
+// UpdateTeamMember sets the permission of the team member, TeamPermissionAdmin or 0.
+func (c *Client) UpdateTeamMember(ctx context.Context, team *Team, member *TeamMember) (bool, error) {
+	if team == nil || member == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/teams/" + strconv.FormatInt(team.Id, 10) + "/members/" + strconv.FormatInt(member.UserId, 10)
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(map[string]int{
+		"permission": member.Permission,
+	})
+
+	req, err := c.newRequest(ctx, http.MethodPut, slug, payloadBuffer)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
+
+func (c *Client) RemoveTeamMember(ctx context.Context, team *Team, member *TeamMember) (bool, error) {
+	if team == nil || member == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	}
+
+	slug := "/api/teams/" + strconv.FormatInt(team.Id, 10) + "/members/" + strconv.FormatInt(member.UserId, 10)
+
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 {
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
+}
+
+// SetTeamMembers makes the members the only members of the team: missing users are added,
+// the permission of the present ones is updated when it differs and the others are removed.
+func (c *Client) SetTeamMembers(ctx context.Context, team *Team, members *[]TeamMember) (bool, error) {
+	if team == nil || members == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	}
+
+	for i := range *members {
+		err := c.resolveTeamMember(ctx, &(*members)[i])
+		if err != nil {
+			return false, err
+		}
+	}
+
+	currentMembers, err := c.GetTeamMembers(ctx, team)
+	if err != nil {
+		return false, err
+	}
+
+EXIST:
+	for i := range *members {
+		member := &(*members)[i]
+		for _, currentMember := range currentMembers {
+			if member.UserId == currentMember.UserId {
+				if member.Permission != currentMember.Permission {
+					_, err = c.UpdateTeamMember(ctx, team, member)
+					if err != nil {
+						return false, err
+					}
+				}
+				continue EXIST
+			}
+		}
+
+		_, err = c.AddTeamMember(ctx, team, member)
+		if err != nil {
+			return false, err
+		}
+	}
+
+OK:
+	for _, currentMember := range currentMembers {
+		for _, member := range *members {
+			if member.UserId == currentMember.UserId {
+				continue OK
+			}
+		}
+
+		_, err = c.RemoveTeamMember(ctx, team, &currentMember)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// resolveTeamMember sets the UserId of a member given by its login or email.
+func (c *Client) resolveTeamMember(ctx context.Context, member *TeamMember) error {
+	if member.UserId > 0 {
+		return nil
+	}
+	if member.Login == "" && member.Email == "" {
+		return newError(ErrValidation, "No UserId, Login, Email has been set for team member")
+	}
+
+	// the members are looked up in the organization, the user lookup needs the server admin
+	user, err := c.LookupOrganizationUser(ctx, member.Login, member.Email)
+	if err != nil {
+		return err
+	}
+	member.UserId = user.Id
+
+	return nil
+}
@@ -2,12 +2,13 @@ package apiv1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -34,69 +35,97 @@ type UserOrganization struct {
 	Role string `json:"role"`
 }
 
-func SearchUsers(query string) (*[]User, error) {
+// SearchUsers returns all the users matching the query (every user if it is empty),
+// walking every page of them.
+func (c *Client) SearchUsers(ctx context.Context, query string) (*[]User, error) {
+	users := make([]User, 0)
+	err := c.WalkUsers(ctx, query, DefaultPerPage, func(page []User) error {
+		users = append(users, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) == 0 {
+		return nil, ErrNotFound
+	}
+	return &users, nil
+}
+
+// WalkUsers calls fn with every page of perPage users matching the query until fn returns an error.
+func (c *Client) WalkUsers(ctx context.Context, query string, perPage int, fn func(users []User) error) error {
+	return walkPages(perPage, func(page Page) (int, int64, error) {
+		users, totalCount, err := c.SearchUsersPage(ctx, query, page)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		return len(users), totalCount, fn(users)
+	})
+}
+
+// SearchUsersPage returns a page of the users matching the query along with the count of all of them.
+func (c *Client) SearchUsersPage(ctx context.Context, query string, page Page) ([]User, int64, error) {
+	page = page.normalize()
 	slug := "/api/users/search"
-	url := grafanaClientSettings.url + slug
 
-	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
 	q := req.URL.Query()
 
 	q.Add("query", query)
+	q.Add("page", strconv.Itoa(page.Page))
+	q.Add("perpage", strconv.Itoa(page.PerPage))
 	req.URL.RawQuery = q.Encode()
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	defer res.Body.Close()
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	if res.StatusCode == 200 {
 		var data = struct {
-			Users []User `json:"users"`
-		}{}
+			TotalCount int64  `json:"totalCount"`
+			Users      []User `json:"users"`
+		}{Users: make([]User, 0)}
 		err = json.Unmarshal(body, &data)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
-		if len(data.Users) == 0 {
-			return nil, errors.New("Empty result")
-		}
-		return &data.Users, nil
+		return data.Users, data.TotalCount, nil
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, 0, newResponseError(res.StatusCode, body)
 }
 
-func GetUser(user *User) (*User, error) {
+func (c *Client) GetUser(ctx context.Context, user *User) (*User, error) {
 	slug := ""
 
 	if user == nil {
-		return nil, errors.New("Nil pointer")
+		return nil, newError(ErrValidation, "Nil pointer")
 	} else if user.Id > 0 {
 		slug = "/api/users/" + strconv.FormatInt(user.Id, 10)
 	} else if user.Email != "" || user.Login != "" {
 		slug = "/api/users/lookup"
 	} else {
-		return nil, errors.New("No Id, Login, Email has been set for user")
+		return nil, newError(ErrValidation, "No Id, Login, Email has been set for user")
 	}
 
-	url := grafanaClientSettings.url + slug
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, nil)
 	if err != nil {
 		return user, err
 	}
@@ -117,9 +146,8 @@ func GetUser(user *User) (*User, error) {
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -139,29 +167,32 @@ func GetUser(user *User) (*User, error) {
 
 		return user, nil
 	} else if res.StatusCode == 404 {
-		return user, errors.New("Empty result")
+		return user, ErrNotFound
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
 }
 
-func UpdateUser(user *User) (*User, error) {
+func (c *Client) UpdateUser(ctx context.Context, user *User) (*User, error) {
+	if user == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if user.Id == 0 {
+		return nil, newError(ErrValidation, "No user id provided")
+	}
 	slug := "/api/users/" + strconv.FormatInt(user.Id, 10)
-	url := grafanaClientSettings.url + slug
 
 	payloadBuffer := new(bytes.Buffer)
 	json.NewEncoder(payloadBuffer).Encode(user)
 
-	req, err := http.NewRequest(http.MethodPut, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodPut, slug, payloadBuffer)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -185,30 +216,33 @@ func UpdateUser(user *User) (*User, error) {
 		}
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
 }
 
-func UpdateUserPassword(user *User) (*User, error) {
-	if user.Id == 0 {
-		return nil, errors.New("No user id provided")
+func (c *Client) UpdateUserPassword(ctx context.Context, user *User) (*User, error) {
+	if user == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	} else if user.Id == 0 {
+		return nil, newError(ErrValidation, "No user id provided")
 	}
 	slug := "/api/admin/users/" + strconv.FormatInt(user.Id, 10) + "/password"
-	url := grafanaClientSettings.url + slug
 	if user.Password == "" {
-		return nil, errors.New("No user password provided")
+		return nil, newError(ErrValidation, "No user password provided")
 	}
-	jsonReq := `{"password":"` + user.Password + `"}`
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(map[string]string{
+		"password": user.Password,
+	})
 
-	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer([]byte(jsonReq)))
+	req, err := c.newRequest(ctx, http.MethodPut, slug, payloadBuffer)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -231,29 +265,27 @@ func UpdateUserPassword(user *User) (*User, error) {
 		}
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
 }
 
-func SetUserGrafanaAdmin(user *User, isAdmin bool) (bool, error) {
+func (c *Client) SetUserGrafanaAdmin(ctx context.Context, user *User, isAdmin bool) (bool, error) {
 	if user == nil {
-		return false, errors.New("Nil pointer")
+		return false, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/admin/users/" + strconv.FormatInt(user.Id, 10) + "/permissions"
-	url := grafanaClientSettings.url + slug
 
 	jsonReq := `{"isGrafanaAdmin": ` + strconv.FormatBool(isAdmin) + "}"
 
-	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer([]byte(jsonReq)))
+	req, err := c.newRequest(ctx, http.MethodPut, slug, bytes.NewBuffer([]byte(jsonReq)))
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -277,30 +309,71 @@ func SetUserGrafanaAdmin(user *User, isAdmin bool) (bool, error) {
 		}
 	}
 
-	return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return false, newResponseError(res.StatusCode, body)
+}
+
+// SetUserDisabled disables or enables the user, a disabled user can not log in and is logged out
+// of its sessions. It sets IsDisabled of the user.
+func (c *Client) SetUserDisabled(ctx context.Context, user *User, isDisabled bool) (bool, error) {
+	if user == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	} else if user.Id == 0 {
+		return false, newError(ErrValidation, "No user id provided")
+	}
+
+	slug := "/api/admin/users/" + strconv.FormatInt(user.Id, 10) + "/enable"
+	if isDisabled {
+		slug = "/api/admin/users/" + strconv.FormatInt(user.Id, 10) + "/disable"
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 {
+		user.IsDisabled = isDisabled
+
+		return true, nil
+	}
+
+	return false, newResponseError(res.StatusCode, body)
 }
 
-func CreateUser(user *User) (*User, error) {
+func (c *Client) CreateUser(ctx context.Context, user *User) (*User, error) {
 	if user == nil {
-		return nil, errors.New("Nil pointer")
+		return nil, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/admin/users"
-	url := grafanaClientSettings.url + slug
 
 	payloadBuffer := new(bytes.Buffer)
 	json.NewEncoder(payloadBuffer).Encode(user)
 
-	req, err := http.NewRequest(http.MethodPost, url, payloadBuffer)
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
 	if err != nil {
 		return user, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return user, err
 	}
@@ -326,28 +399,26 @@ func CreateUser(user *User) (*User, error) {
 		return user, nil
 	}
 
-	return user, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return user, newResponseError(res.StatusCode, body)
 
 }
 
-func DeleteUser(user *User) (bool, error) {
+func (c *Client) DeleteUser(ctx context.Context, user *User) (bool, error) {
 	if user == nil {
-		return false, errors.New("Nil pointer")
+		return false, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/admin/users/" + strconv.FormatInt(user.Id, 10)
-	url := grafanaClientSettings.url + slug
 
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -371,27 +442,25 @@ func DeleteUser(user *User) (bool, error) {
 		}
 	}
 
-	return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return false, newResponseError(res.StatusCode, body)
 
 }
 
-func GetOrganizationsByUser(user *User) (*[]UserOrganization, error) {
+func (c *Client) GetOrganizationsByUser(ctx context.Context, user *User) (*[]UserOrganization, error) {
 	if user == nil {
-		return nil, errors.New("Nil pointer")
+		return nil, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/users/" + strconv.FormatInt(user.Id, 10) + "/orgs"
-	url := grafanaClientSettings.url + slug
 
-	req, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -413,147 +482,299 @@ func GetOrganizationsByUser(user *User) (*[]UserOrganization, error) {
 		return &organizations, nil
 	}
 
-	return nil, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return nil, newResponseError(res.StatusCode, body)
 }
 
-func SetUserOrganizations(user *User, organizations *[]UserOrganization) (bool, error) {
-	if user == nil {
-		return false, errors.New("Nil pointer")
+// GetCurrentUserOrganizations returns the organizations of the authenticated user.
+func (c *Client) GetCurrentUserOrganizations(ctx context.Context) ([]UserOrganization, error) {
+	slug := "/api/user/orgs"
+
+	req, err := c.newRequest(ctx, http.MethodGet, slug, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		organizations := make([]UserOrganization, 0)
+		err = json.Unmarshal(body, &organizations)
+		if err != nil {
+			return nil, err
+		}
+
+		return organizations, nil
+	}
+
+	return nil, newResponseError(res.StatusCode, body)
+}
+
+// Modes of SetUserOrganizationsWith
+const (
+	// MembershipReplace adds the user to the listed organizations and removes it from the others
+	MembershipReplace = "replace"
+	// MembershipMerge only adds the user to the listed organizations or updates its role in them
+	MembershipMerge = "merge"
+	// MembershipRemove removes the user from the listed organizations
+	MembershipRemove = "remove"
+)
+
+// UserOrganizationsOptions tunes the way SetUserOrganizationsWith changes the organizations of a user.
+type UserOrganizationsOptions struct {
+	// Mode is MembershipReplace by default.
+	Mode string
+	// ManagedPrefix limits the organizations touched to the ones named with it when set:
+	// listing another organization is refused and MembershipReplace keeps the user in the others.
+	ManagedPrefix string
+}
+
+// Results of a change of the organizations of a user
+const (
+	OrganizationAdded       = "added"
+	OrganizationRoleChanged = "role-changed"
+	OrganizationRemoved     = "removed"
+	// OrganizationUnchanged is the result of a listed organization that needs no change
+	OrganizationUnchanged = "unchanged"
+)
+
+// Statuses of a planned user organization change
+const (
+	ChangeApplied    = "applied"
+	ChangeFailed     = "failed"
+	ChangeRolledBack = "rolled-back"
+	// ChangeSkipped is the status of a change not tried after a failed one, and of a listed
+	// organization that needs no change
+	ChangeSkipped = "skipped"
+	// ChangeSkippedLastAdmin is the status of a change left out, the user being the last admin
+	// of the organization
+	ChangeSkippedLastAdmin = "skipped-last-admin"
+)
+
+// UserOrganizationChange is a change planned to a single organization of a user. Result is the
+// kind of change, PreviousRole the role the user had before and Status what became of it.
+type UserOrganizationChange struct {
+	OrgId        int64  `json:"orgId"`
+	Name         string `json:"name"`
+	Role         string `json:"role,omitempty"`
+	PreviousRole string `json:"previousRole,omitempty"`
+	Result       string `json:"result"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+}
+
+// SetUserOrganizations makes the organizations the only ones of the user, see SetUserOrganizationsWith.
+func (c *Client) SetUserOrganizations(ctx context.Context, user *User, organizations *[]UserOrganization) ([]UserOrganizationChange, error) {
+	return c.SetUserOrganizationsWith(ctx, user, organizations, UserOrganizationsOptions{})
+}
+
+// SetUserOrganizationsWith adds the user to the organizations, found by Id or Name, with their Role
+// (Viewer by default) or removes it from them, the way set by the Mode of the options, and returns
+// a change for every listed organization, OrganizationUnchanged when it needs none, and for every
+// other organization the user is removed from. The user is kept in, and with its admin role, the
+// organizations it is the last admin of. The changes are all or nothing: when one fails, the ones made before are undone, the
+// following ones are skipped and the error is returned along with every planned change.
+func (c *Client) SetUserOrganizationsWith(ctx context.Context, user *User, organizations *[]UserOrganization, options UserOrganizationsOptions) ([]UserOrganizationChange, error) {
+	if user == nil || organizations == nil {
+		return nil, newError(ErrValidation, "Nil pointer")
+	}
+
+	if options.Mode == "" {
+		options.Mode = MembershipReplace
+	}
+	switch options.Mode {
+	case MembershipReplace, MembershipMerge, MembershipRemove:
+	default:
+		return nil, newError(ErrValidation, "Unknown mode "+options.Mode)
 	}
 
 	if len(*organizations) == 0 {
-		return false, errors.New("Empty organization list")
+		return nil, newError(ErrValidation, "Empty organization list")
 	}
 
-	currentOrganizations, err := GetOrganizationsByUser(user)
+	currentOrganizations, err := c.GetOrganizationsByUser(ctx, user)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-EXIST:
+	// the organizations are looked up before any change is made
+	listed := make([]UserOrganization, 0, len(*organizations))
 	for _, userOrganization := range *organizations {
 		organization := Organization{
 			Id:   userOrganization.Id,
 			Name: userOrganization.Name,
 		}
 
-		_, err = GetOrganization(&organization)
-		if organization.Id == 0 {
-			return false, errors.New("Organization " + organization.Name + " doesn't exist")
+		_, err = c.GetOrganization(ctx, &organization)
+		if organization.Id == 0 || errors.Is(err, ErrNotFound) {
+			return nil, newError(ErrNotFound, "Organization "+organization.Name+" doesn't exist")
+		} else if err != nil {
+			return nil, err
 		}
 
-		if userOrganization.Role == "" {
-			userOrganization.Role = "Viewer"
+		if !strings.HasPrefix(organization.Name, options.ManagedPrefix) {
+			return nil, newError(ErrValidation, "Organization "+organization.Name+" is not managed")
 		}
 
-		userAlreadyInOrg := false
+		if userOrganization.Role == "" {
+			userOrganization.Role = RoleViewer
+		}
+		userOrganization.Id, userOrganization.Name = organization.Id, organization.Name
+		listed = append(listed, userOrganization)
+	}
 
+	// every change to make, in order
+	changes := make([]UserOrganizationChange, 0)
+	if options.Mode == MembershipRemove {
+		for _, userOrganization := range listed {
+			currentOrganization := findUserOrganization(*currentOrganizations, userOrganization.Id)
+			if currentOrganization != nil {
+				changes = append(changes, UserOrganizationChange{OrgId: currentOrganization.Id, Name: currentOrganization.Name, PreviousRole: currentOrganization.Role, Result: OrganizationRemoved})
+			} else {
+				changes = append(changes, UserOrganizationChange{OrgId: userOrganization.Id, Name: userOrganization.Name, Result: OrganizationUnchanged})
+			}
+		}
+	} else {
+		for _, userOrganization := range listed {
+			currentOrganization := findUserOrganization(*currentOrganizations, userOrganization.Id)
+			if currentOrganization == nil {
+				changes = append(changes, UserOrganizationChange{OrgId: userOrganization.Id, Name: userOrganization.Name, Role: userOrganization.Role, Result: OrganizationAdded})
+			} else if currentOrganization.Role != userOrganization.Role {
+				changes = append(changes, UserOrganizationChange{OrgId: userOrganization.Id, Name: userOrganization.Name, Role: userOrganization.Role, PreviousRole: currentOrganization.Role, Result: OrganizationRoleChanged})
+			} else {
+				changes = append(changes, UserOrganizationChange{OrgId: userOrganization.Id, Name: userOrganization.Name, Role: userOrganization.Role, PreviousRole: currentOrganization.Role, Result: OrganizationUnchanged})
+			}
+		}
+	}
+	if options.Mode == MembershipReplace {
 		for _, currentOrganization := range *currentOrganizations {
-			if organization.Name == currentOrganization.Name {
-				userAlreadyInOrg = true
-				if userOrganization.Role == currentOrganization.Role {
-					continue EXIST
-				}
+			if findUserOrganization(listed, currentOrganization.Id) == nil && strings.HasPrefix(currentOrganization.Name, options.ManagedPrefix) {
+				changes = append(changes, UserOrganizationChange{OrgId: currentOrganization.Id, Name: currentOrganization.Name, PreviousRole: currentOrganization.Role, Result: OrganizationRemoved})
 			}
 		}
+	}
 
-		httpMethod := ""
-		slug := ""
-		url := ""
-		jsonReq := ""
-
-		if userAlreadyInOrg == true {
-			slug = "/api/orgs/" + strconv.FormatInt(organization.Id, 10) + "/users/" + strconv.FormatInt(user.Id, 10)
-			url = grafanaClientSettings.url + slug
-
-			jsonReq = `{"role": "` + userOrganization.Role + `"}`
-			httpMethod = http.MethodPatch
-		} else {
-			slug = "/api/orgs/" + strconv.FormatInt(organization.Id, 10) + "/users/"
-			url = grafanaClientSettings.url + slug
-
-			jsonReq = `{"loginOrEmail": "` + user.Email + `", "role": "` + userOrganization.Role + `"}`
-			httpMethod = http.MethodPost
-		}
+	for i := range changes {
+		changes[i].Status = ChangeSkipped
+	}
 
-		req, err := http.NewRequest(httpMethod, url, bytes.NewBuffer([]byte(jsonReq)))
-		if err != nil {
-			return false, err
+	for i := range changes {
+		if changes[i].Result == OrganizationUnchanged {
+			continue
 		}
 
-		req.Header.Set("Content-Type", "application/json; charset=utf-8")
-		req.Header.Add("Accept", "application/json")
-		req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
-
-		res, err := client.Do(req)
-		if err != nil {
-			return false, err
+		err = c.applyUserOrganizationChange(ctx, user, changes[i], false)
+		if errors.Is(err, ErrLastAdmin) {
+			c.logger.Printf("Got error: %v\n", err.Error()+" ("+changes[i].Name+")")
+			changes[i].Status = ChangeSkippedLastAdmin
+			changes[i].Error = err.Error()
+			continue
+		} else if err == nil {
+			changes[i].Status = ChangeApplied
+			continue
 		}
 
-		defer res.Body.Close()
+		changes[i].Status = ChangeFailed
+		changes[i].Error = err.Error()
 
-		body, err := io.ReadAll(res.Body)
-		if err != nil {
-			return false, err
+		// the rollback goes on when the request has timed out, every call being bounded by the client timeout
+		rollbackCtx := ctx
+		if ctx.Err() != nil {
+			rollbackCtx = context.Background()
 		}
-		if res.StatusCode != 200 {
-			return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+		for j := i - 1; j >= 0; j-- {
+			if changes[j].Status != ChangeApplied {
+				continue
+			}
+			rollbackErr := c.applyUserOrganizationChange(rollbackCtx, user, changes[j], true)
+			if rollbackErr != nil {
+				c.logger.Printf("Got error: %v\n", "Rollback failed: "+rollbackErr.Error()+" ("+changes[j].Name+")")
+				changes[j].Error = "Rollback failed: " + rollbackErr.Error()
+				continue
+			}
+			changes[j].Status = ChangeRolledBack
 		}
+
+		return changes, err
 	}
 
-	deleteFromOrganizations := []UserOrganization{}
+	return changes, nil
+}
 
-OK:
-	for _, userCurrentOrganization := range *currentOrganizations {
-		for _, userOrganization := range *organizations {
-			if userOrganization.Name == userCurrentOrganization.Name {
-				continue OK
-			}
-		}
-		deleteFromOrganizations = append(deleteFromOrganizations, userCurrentOrganization)
-	}
+// applyUserOrganizationChange makes the change to the organization of the user, or undoes it.
+func (c *Client) applyUserOrganizationChange(ctx context.Context, user *User, change UserOrganizationChange, undo bool) error {
+	organization := Organization{Id: change.OrgId, Name: change.Name}
 
-	for _, userOrganization := range deleteFromOrganizations {
-		organization := Organization{
-			Id:   userOrganization.Id,
-			Name: userOrganization.Name,
+	var err error
+	switch {
+	case change.Result == OrganizationAdded && !undo, change.Result == OrganizationRemoved && undo:
+		role := change.Role
+		if undo {
+			role = change.PreviousRole
 		}
-		_, err := DeleteUserFromOrganization(user, &organization)
-		if err != nil {
-			if err.Error() == "Cannot remove last organization admin" {
-				fmt.Printf("Got error: %v\n", err.Error()+" ("+organization.Name+")")
-				continue
-			}
-			return false, err
+		_, err = c.AddUserToOrganization(ctx, user, &organization, role)
+	case change.Result == OrganizationAdded && undo, change.Result == OrganizationRemoved && !undo:
+		_, err = c.DeleteUserFromOrganization(ctx, user, &organization)
+	case change.Result == OrganizationRoleChanged:
+		role := change.Role
+		if undo {
+			role = change.PreviousRole
 		}
+		_, err = c.UpdateUserInOrganization(ctx, user, &organization, role)
 	}
 
-	currentOrganizations, err = GetOrganizationsByUser(user)
-	if err != nil {
-		return false, err
+	return err
+}
+
+// findUserOrganization returns the organization of the list with the id, nil if there is none.
+func findUserOrganization(organizations []UserOrganization, id int64) *UserOrganization {
+	for i, organization := range organizations {
+		if organization.Id == id {
+			return &organizations[i]
+		}
 	}
 
-	return true, nil
+	return nil
 }
 
-func DeleteUserFromOrganization(user *User, organization *Organization) (bool, error) {
+func (c *Client) AddUserToOrganization(ctx context.Context, user *User, organization *Organization, role string) (bool, error) {
 	if user == nil || organization == nil {
-		return false, errors.New("Nil pointer")
+		return false, newError(ErrValidation, "Nil pointer")
 	}
 
-	slug := "/api/orgs/" + strconv.FormatInt(organization.Id, 10) + "/users/" + strconv.FormatInt(user.Id, 10)
-	url := grafanaClientSettings.url + slug
+	slug := "/api/orgs/" + strconv.FormatInt(organization.Id, 10) + "/users"
 
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	loginOrEmail := user.Login
+	if loginOrEmail == "" {
+		loginOrEmail = user.Email
+	}
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(map[string]string{
+		"loginOrEmail": loginOrEmail,
+		"role":         role,
+	})
+
+	req, err := c.newRequest(ctx, http.MethodPost, slug, payloadBuffer)
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -572,44 +793,73 @@ func DeleteUserFromOrganization(user *User, organization *Organization) (bool, e
 			return false, err
 		}
 
-		if data["message"] == "User removed from organization" {
+		if data["message"] == "User added to organization" {
 			return true, nil
 		}
-	} else if res.StatusCode == 400 {
-		var data map[string]interface{}
-		err = json.Unmarshal(body, &data)
-		if err != nil {
-			return false, err
-		}
+	}
 
-		if data["message"] == "Cannot remove last organization admin" {
-			err = errors.New("Cannot remove last organization admin")
-			return false, err
-		}
+	return false, newResponseError(res.StatusCode, body)
+}
+
+// UpdateUserInOrganization changes the role of the user in the organization, Grafana refuses to
+// leave the organization without an admin with ErrLastAdmin.
+func (c *Client) UpdateUserInOrganization(ctx context.Context, user *User, organization *Organization, role string) (bool, error) {
+	if user == nil || organization == nil {
+		return false, newError(ErrValidation, "Nil pointer")
+	} else if user.Id == 0 {
+		return false, newError(ErrValidation, "No user id provided")
+	}
+
+	slug := "/api/orgs/" + strconv.FormatInt(organization.Id, 10) + "/users/" + strconv.FormatInt(user.Id, 10)
+
+	payloadBuffer := new(bytes.Buffer)
+	json.NewEncoder(payloadBuffer).Encode(map[string]string{
+		"role": role,
+	})
+
+	req, err := c.newRequest(ctx, http.MethodPatch, slug, payloadBuffer)
+	if err != nil {
+		return false, err
 	}
 
-	return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == 200 {
+		return true, nil
+	}
 
+	return false, newResponseError(res.StatusCode, body)
 }
 
-func CreateUserApiToken(user *User, organization *Organization) (bool, error) {
+func (c *Client) DeleteUserFromOrganization(ctx context.Context, user *User, organization *Organization) (bool, error) {
 	if user == nil || organization == nil {
-		return false, errors.New("Nil pointer")
+		return false, newError(ErrValidation, "Nil pointer")
 	}
 
 	slug := "/api/orgs/" + strconv.FormatInt(organization.Id, 10) + "/users/" + strconv.FormatInt(user.Id, 10)
-	url := grafanaClientSettings.url + slug
 
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, slug, nil)
 	if err != nil {
 		return false, err
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Add("Accept", "application/json")
-	req.SetBasicAuth(grafanaClientSettings.login, grafanaClientSettings.password)
 
-	res, err := client.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -631,19 +881,8 @@ func CreateUserApiToken(user *User, organization *Organization) (bool, error) {
 		if data["message"] == "User removed from organization" {
 			return true, nil
 		}
-	} else if res.StatusCode == 400 {
-		var data map[string]interface{}
-		err = json.Unmarshal(body, &data)
-		if err != nil {
-			return false, err
-		}
-
-		if data["message"] == "Cannot remove last organization admin" {
-			err = errors.New("Cannot remove last organization admin")
-			return false, err
-		}
 	}
 
-	return false, errors.New("Got response: " + strconv.Itoa(res.StatusCode) + ", body: " + string(body))
+	return false, newResponseError(res.StatusCode, body)
 
 }
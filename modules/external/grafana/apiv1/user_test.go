@@ -0,0 +1,100 @@
+package apiv1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newOrganizationsStub answers as Grafana for user 5, the last admin of organization 2
+// and a viewer of organization 3, organization 4 being the other one.
+func newOrganizationsStub(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	names := map[string]string{"2": "team-a", "3": "team-b", "4": "team-c"}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		orgId := strings.TrimPrefix(r.URL.Path, "/api/orgs/")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/users/5/orgs":
+			fmt.Fprint(w, `[{"orgId":2,"name":"team-a","role":"Admin"},{"orgId":3,"name":"team-b","role":"Viewer"}]`)
+		case r.Method == http.MethodGet && names[orgId] != "":
+			fmt.Fprintf(w, `{"id":%s,"name":"%s"}`, orgId, names[orgId])
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/orgs/2/users/5":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message":"Cannot remove last organization admin"}`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/orgs/3/users/5":
+			fmt.Fprint(w, `{"message":"User removed from organization"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/orgs/4/users":
+			fmt.Fprint(w, `{"message":"User added to organization"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not found"}`)
+		}
+	}))
+}
+
+func TestSetUserOrganizationsWithStatuses(t *testing.T) {
+	stub := newOrganizationsStub(t)
+	defer stub.Close()
+
+	type change struct {
+		orgId  int64
+		result string
+		status string
+	}
+	cases := []struct {
+		mode          string
+		organizations []UserOrganization
+		want          []change
+	}{
+		{
+			mode:          MembershipRemove,
+			organizations: []UserOrganization{{Id: 2}, {Id: 3}, {Id: 4}},
+			want: []change{
+				{2, OrganizationRemoved, ChangeSkippedLastAdmin},
+				{3, OrganizationRemoved, ChangeApplied},
+				{4, OrganizationUnchanged, ChangeSkipped},
+			},
+		},
+		{
+			mode:          MembershipMerge,
+			organizations: []UserOrganization{{Id: 3, Role: RoleViewer}, {Id: 4, Role: "Editor"}},
+			want: []change{
+				{3, OrganizationUnchanged, ChangeSkipped},
+				{4, OrganizationAdded, ChangeApplied},
+			},
+		},
+		{
+			mode:          MembershipReplace,
+			organizations: []UserOrganization{{Id: 3, Role: RoleViewer}},
+			want: []change{
+				{3, OrganizationUnchanged, ChangeSkipped},
+				{2, OrganizationRemoved, ChangeSkippedLastAdmin},
+			},
+		},
+	}
+
+	client := NewClient(stub.URL, "admin", "admin")
+	for _, c := range cases {
+		t.Run(c.mode, func(t *testing.T) {
+			changes, err := client.SetUserOrganizationsWith(context.Background(), &User{Id: 5}, &c.organizations, UserOrganizationsOptions{Mode: c.mode})
+			if err != nil {
+				t.Fatalf("SetUserOrganizationsWith: %v", err)
+			}
+
+			got := make([]change, 0, len(changes))
+			for _, userOrganizationChange := range changes {
+				got = append(got, change{userOrganizationChange.OrgId, userOrganizationChange.Result, userOrganizationChange.Status})
+			}
+			if fmt.Sprint(got) != fmt.Sprint(c.want) {
+				t.Errorf("Changes %v, want %v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	grafana "grafana-adapter/modules/external/grafana/apiv1"
+)
+
+// errorStatus maps an error returned by the grafana client to the status code
+// the adapter responds with.
+func errorStatus(err error) int {
+	var grafanaError *grafana.Error
+
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, grafana.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, grafana.ErrLastAdmin):
+		return http.StatusConflict
+	case errors.Is(err, grafana.ErrConflict):
+		// the entity has been changed since the version the request is based on
+		if errors.As(err, &grafanaError) && grafanaError.StatusCode == http.StatusPreconditionFailed {
+			return http.StatusPreconditionFailed
+		}
+		return http.StatusConflict
+	case errors.Is(err, grafana.ErrValidation):
+		if errors.As(err, &grafanaError) && grafanaError.StatusCode == http.StatusUnprocessableEntity {
+			return http.StatusUnprocessableEntity
+		}
+		return http.StatusBadRequest
+	case errors.Is(err, grafana.ErrPermission):
+		return http.StatusForbidden
+	case errors.Is(err, grafana.ErrUpstream):
+		return http.StatusBadGateway
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,34 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/flamego/flamego"
+)
+
+// etag returns the ETag of an entity (dashboard, folder, datasource) at the Grafana version
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// preconditionFailed answers the request with 412 when it has an If-Match header none of the
+// ETags of which is the one of the version, "*" matches any version. A request without If-Match
+// is never refused.
+func preconditionFailed(c flamego.Context, version int) bool {
+	header := c.Request().Header.Get("If-Match")
+	if header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag(version) {
+			return false
+		}
+	}
+
+	c.ResponseWriter().WriteHeader(http.StatusPreconditionFailed)
+	return true
+}
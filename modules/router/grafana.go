@@ -1,16 +1,16 @@
 package router
 
 import (
-	"crypto/md5"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/flamego/auth"
@@ -27,13 +27,99 @@ func init() {
 
 func Start() {
 	f := flamego.Classic()
-	f.Map(log.New(os.Stdout, "[grafana-adapter] ", 0))
+	logger := log.New(os.Stdout, "[grafana-adapter] ", 0)
+	f.Map(logger)
+	f.Use(requestTimeout)
+
+	clients := newGrafanaClients(logger)
+	f.Map(clients[settings.DefaultGrafanaInstance])
+	accesses := newOrganizationAccesses()
+	f.Map(accesses[settings.DefaultGrafanaInstance])
+
+	grafanaRoutes(f)
+	instanceRoutes(f, clients, accesses)
+
+	//index
+	f.Get("/", func(c flamego.Context) string {
+		c.ResponseWriter().Header().Add("Content-Type", "text/html")
+		return `<html><head><title>Grafana Adapter</title></head><body style="display: -webkit-flex; display: flex; -webkit-align-items: center; align-items: center; -webkit-justify-content: center; justify-content: center;"><svg xmlns="http://www.w3.org/2000/svg" viewBox="22.4 66 51 51" width="255" height="255"><g transform="translate(-11.520834,168.12362)" style="fill:none;stroke:#181b1f;stroke-opacity:1"><path style="fill:none;fill-opacity:1;stroke:#181b1f;stroke-width:1.16779;stroke-miterlimit:4;stroke-dasharray:none;stroke-opacity:1" d="m 76.721537,-79.583115 1.20611,-0.206027 2.400742,0.413747 3.709327,2.134183 v 1.270057 l -3.709327,2.134183 -2.400742,0.413747 -1.20611,-0.206028 -0.846379,3.158659 1.147605,0.424625 1.872252,1.558704 2.145246,3.702886 -0.635039,1.09993 -4.279473,-0.0063 -2.285954,-0.84212 -0.941542,-0.781486 -2.312334,2.312334 0.781486,0.941541 0.84212,2.285956 0.0063,4.279473 -1.09993,0.635039 -3.702886,-2.145246 -1.558704,-1.872252 -0.424625,-1.147605 -3.158659,0.846379 0.206028,1.206112 -0.413747,2.40074 -2.134184,3.709327 h -1.270056 l -2.134184,-3.709327 -0.413747,-2.40074 0.206028,-1.206112 -3.158659,-0.846379 -0.424625,1.147605 -1.558704,1.872252 -3.702886,2.145246 -1.09993,-0.635039 0.0063,-4.279473 0.84212,-2.285956 0.781486,-0.941541 -2.312334,-2.312334 -0.941542,0.781486 -2.285954,0.84212 -4.279473,0.0063 -0.635039,-1.09993 2.145246,-3.702886 1.872252,-1.558704 1.147605,-0.424625 -0.846379,-3.158659 -1.20611,0.206028 -2.400742,-0.413747 -3.709327,-2.134183 v -1.270057 l 3.709327,-2.134183 2.400742,-0.413747 1.20611,0.206027 0.846379,-3.158658 -1.147605,-0.424625 -1.872252,-1.558704 -2.145246,-3.702886 0.635039,-1.09993 4.279473,0.0063 2.285954,0.84212 0.941542,0.781487 2.312334,-2.312335 -0.781486,-0.941543 -0.84212,-2.285952 -0.0063,-4.279474 1.09993,-0.635038 3.702886,2.145244 1.558704,1.872252 0.424625,1.147606 3.158659,-0.846379 -0.206028,-1.20611 0.413747,-2.400742 2.134184,-3.709328 h 1.270056 l 2.134184,3.709328 0.413747,2.400742 -0.206028,1.20611 3.158659,0.846379 0.424625,-1.147606 1.558704,-1.872252 3.702886,-2.145244 1.09993,0.635038 -0.0063,4.279474 -0.84212,2.285952 -0.781486,0.941543 2.312334,2.312335 0.941542,-0.781487 2.285954,-0.84212 4.279473,-0.0063 0.635039,1.09993 -2.145246,3.702886 -1.872252,1.558704 -1.147605,0.424625 z"/><path style="fill:none;fill-rule:evenodd;stroke:#181b1f;stroke-width:0.3;stroke-linecap:butt;stroke-linejoin:miter;stroke-miterlimit:4;stroke-dasharray:1.2, 0.6, 0.3, 0.6;stroke-dashoffset:0;stroke-opacity:1" d="m 65.296341,-55.946924 c 0,0 4.006185,-1.028639 6.875738,-3.345164 3.112133,-2.512353 6.03531,-5.231873 7.839866,-11.174642 1.814931,-5.976939 0.245709,-11.529764 0.245709,-11.529764"/> </g> <g transform="translate(90.604454,163.0733)" style="fill:none;stroke:#181b1f;stroke-opacity:1"> <path style="fill:#181b1f;fill-opacity:1;stroke:#181b1f;stroke-width:0.244842;stroke-opacity:1" d="m -25.403747,-74.532795 c 0.995674,0.259703 4.450542,-0.520083 4.133957,0.66916 -1.476606,5.546838 -3.391312,3.457763 -4.859548,6.346846 0.81556,-5.143325 0.374266,-12.942475 -9.500961,-19.296605 -3.31201,-1.292251 -7.804906,-1.710191 -11.444118,-0.551766 -3.815007,1.214384 -7.693639,3.811627 -9.455049,7.406963 -2.926055,5.972575 -4.115684,15.823906 -0.09631,19.952237 4.019377,4.12833 16.783249,6.057468 16.783249,6.057468 l 0.208539,1.190747 -0.418793,2.370163 -2.160214,3.662081 h -1.285549 l -2.160213,-3.662081 -0.418793,-2.370163 0.208539,-1.190747 -3.197184,-0.835598 -0.429803,1.132988 -1.577717,1.848404 -3.74805,2.117921 -1.113346,-0.62695 0.0065,-4.224965 0.852391,-2.256836 0.791018,-0.929549 -2.34054,-2.282883 -0.953025,0.771532 -2.313835,0.831394 -4.331668,0.0063 -0.642783,-1.085921 2.171409,-3.65572 1.895087,-1.538851 1.161603,-0.419215 -0.856702,-3.118427 -1.220821,0.203402 -2.430022,-0.408476 -3.754568,-2.107 v -1.25388 l 3.754568,-2.107 2.430022,-0.408476 1.220821,0.203402 0.856702,-3.118427 -1.161603,-0.419215 -1.895087,-1.538851 -2.171409,-3.65572 0.642783,-1.085921 4.331668,0.0063 2.313835,0.831394 0.953025,0.771532 2.34054,-2.282883 -0.791018,-0.929549 -0.852391,-2.256836 -0.0065,-4.224965 1.113346,-0.62695 3.74805,2.117921 1.577717,1.848404 0.429803,1.132988 3.197184,-0.835598 -0.208539,-1.190747 0.418793,-2.370163 2.160213,-3.662081 h 1.285549 l 2.160214,3.662081 0.418793,2.370163 -0.208539,1.190747 3.197183,0.835598 0.429803,-1.132988 1.577715,-1.848404 3.748046,-2.117921 1.113347,0.62695 -0.0065,4.224965 -0.852392,2.256836 -0.791017,0.929549 2.340539,2.282883 0.953025,-0.771532 2.313833,-0.831394 c 0,0 3.864978,-0.464225 4.363438,0.158551 0,0 -1.774762,4.071397 -2.703826,4.512106 l -1.913259,2.02275 z"/> </g> <path d="m 60.945554,79.828945 c -3.56334,-4.16028 -9.55703,-5.98062 -14.42394,-5.62389 -4.8669,0.35673 -9.07393,3.2743 -11.98739,6.83327 -2.87675,3.51414 -4.60608,8.40632 -4.27654,12.93579 0.2986,4.10429 2.47934,8.276235 5.53084,11.037155 7.32934,6.1694 14.21778,7.67727 18.49458,3.15022 5.39942,-4.52307 6.97817,-13.242135 3.08851,-18.571145 -4.42531,-6.06288 -13.67462,-4.9983 -16.01631,1.81537 -0.64012,1.95159 -0.25617,4.50649 0.87661,6.09897 1.13278,1.59248 3.29474,2.519745 4.55026,2.549135 0.2488,0.002 0.49282,-0.0249 0.72414,-0.05599 l 0.20056,-0.0362 c 0.25564,-0.0525 0.42014,-0.3025 0.36741,-0.55804 -0.0467,-0.22039 -0.24509,-0.3752 -0.47021,-0.36647 l -0.1672,0.003 c -0.19143,-0.004 -0.38386,-0.01 -0.5783,-0.0456 -1.63939,-0.30275 -3.61141,-2.60788 -3.6627,-4.74429 -0.0513,-2.1364 2.19753,-4.81163 4.47931,-5.24314 2.28177,-0.43152 5.25781,0.75646 6.49275,3.17014 1.23494,2.41368 0.48466,6.67475 -1.5365,8.91072 -3.01055,3.33051 -8.494872,3.74582 -11.84404,1.58931 -6.216826,-4.00298 -6.930182,-11.704962 -3.65667,-17.37485 2.992971,-5.183978 10.919238,-8.548925 17.64642,-6.48077 3.26282,1.05432 5.7649,2.51848 7.80348,5.58608 0.62978,0.94767 1.0755,1.93042 1.4304,3.09939 0.27695,0.91224 0.82549,-0.59449 1.01429,-2.23306 0.3006,-2.60887 -1.18244,-1.94087 -4.07976,-5.44511 z" style="fill:#181b1f;fill-opacity:1;stroke-width:0.491871"/></svg></body></html>`
+	})
+
+	if settings.Server.Login != "" && settings.Server.Password != "" {
+		f.Use(auth.Basic(settings.Server.Login, settings.Server.Password))
+	}
+	f.Run(settings.Server.Host, settings.Server.Port)
+}
+
+// dashboardSearchConcurrency is the number of organizations searched at once by /dashboards/search.
+const dashboardSearchConcurrency = 4
+
+// grafanaRoutes registers the routes served against a single Grafana instance,
+// the *grafana.Client of which is injected by the enclosing group.
+func grafanaRoutes(f *flamego.Flame) {
+	// reachableOrganizations returns every organization the client reaches, a token only
+	// reaches the organization it belongs to
+	reachableOrganizations := func(ctx context.Context, client *grafana.Client) ([]grafana.Organization, error) {
+		if client.UsesToken() {
+			organization, err := client.GetCurrentOrganization(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			return []grafana.Organization{*organization}, nil
+		}
+
+		return client.GetOrganizations(ctx)
+	}
+
+	// getOrganization looks the organization up by id or name
+	getOrganization := func(ctx context.Context, client *grafana.Client, uid string) (grafana.Organization, error) {
+		organization := grafana.Organization{}
+		id, _ := strconv.ParseInt(uid, 10, 64)
+
+		// a token only reaches the organization it belongs to
+		if client.UsesToken() {
+			current, err := client.GetCurrentOrganization(ctx)
+			if err != nil {
+				return organization, err
+			}
+			if current.Id != id && current.Name != uid {
+				return organization, grafana.ErrNotFound
+			}
+
+			return *current, nil
+		}
+
+		if id > 0 {
+			organization.Id = id
+			_, err := client.GetOrganization(ctx, &organization)
+			if err != nil {
+				return grafana.Organization{}, err
+			}
+		}
+
+		if len(uid) > 0 && organization.Name == "" {
+			reName := regexp.MustCompile(`^([\p{L}\d\s_!-\.@|\]\[\(\)]+)*$`)
+			if reName.MatchString(uid) {
+				organization.Name = uid
+			} else {
+				return grafana.Organization{}, errors.New("Unable to parse organization name")
+			}
+
+			_, err := client.GetOrganization(ctx, &organization)
+			if err != nil {
+				return grafana.Organization{}, err
+			}
+		}
+
+		return organization, nil
+	}
 
 	/*
 	   - USERS -
-	   Retieving all users:
+	   Retieving all users (every list route takes the page and perpage query parameters):
 	   GET
-	   .../users
+	   .../users/ (.../users/?page=2&perpage=100)
 
 	   Retieving | Deleting single user:
 	   GET | DELETE
@@ -45,60 +131,193 @@ func Start() {
 	   Creating user:
 	   POST
 	   .../users/ (data: {})
+
+	   Updating profile (login, email, name, theme) of user:
+	   PUT
+	   .../users/{id*} (data: {"name": "Test", "email": "test@test.test"})
+
+	   Setting | generating (empty data) password of user:
+	   POST
+	   .../users/{id*}/password (data: {"password": "t3st"})
+
+	   Disabling | enabling user:
+	   POST
+	   .../users/{id*}/disable || .../users/{id*}/enable
+
+	   Granting | revoking server admin of user:
+	   PUT
+	   .../users/{id*}/admin (data: {"isGrafanaAdmin": true})
+
+	   Retrieving organizations of user:
+	   GET
+	   .../users/{id*}/organizations
+
+	   Adding user to | Removing user from organization (by id or name):
+	   POST | DELETE
+	   .../users/{id*}/organizations/{org} (.../users/login=test/organizations/test, data: {"role": "Editor"})
+
+	   Switching current organization of user:
+	   PUT
+	   .../users/{id*}/current-organization (data: {"orgId": 11} || {"name": "test"})
 	*/
+	searchUsers := func(c flamego.Context, client *grafana.Client, query string) string {
+		page, paged, err := pageQuery(c)
+		if err != nil {
+			c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+			return err.Error()
+		}
+
+		var users []grafana.User
+		if paged {
+			var totalCount int64
+			users, totalCount, err = client.SearchUsersPage(c.Request().Context(), query, page)
+			if err == nil {
+				writePageHeaders(c, page, len(users), totalCount)
+			}
+		} else {
+			users = make([]grafana.User, 0)
+			err = client.WalkUsers(c.Request().Context(), query, grafana.DefaultPerPage, func(page []grafana.User) error {
+				users = append(users, page...)
+				return nil
+			})
+			if err == nil {
+				c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(users)))
+			}
+		}
+		if err != nil {
+			log.Print("Got error: " + err.Error())
+			c.ResponseWriter().WriteHeader(errorStatus(err))
+			return "null"
+		}
+
+		jsonResponse, err := json.Marshal(users)
+		if err != nil {
+			log.Print("Got error: " + err.Error())
+			c.ResponseWriter().WriteHeader(errorStatus(err))
+			return "null"
+		}
+		c.ResponseWriter().Header().Add("Content-Type", "application/json")
+		return string(jsonResponse)
+	}
+
+	// withUser resolves the {id} user by id or by the id=, login= and email= selectors
+	withUser := func(c flamego.Context, client *grafana.Client) {
+		user := &grafana.User{}
+		c.Map(user)
+		id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+		if id > 0 {
+			user.Id = id
+		} else {
+			r := regexp.MustCompile(`(?m)^(id|login|email)=([\p{L}\d_!-\.@]+)$`)
+			for _, s := range strings.Split(c.Param("id"), ",") {
+				parsed := r.FindStringSubmatch(s)
+				if parsed == nil || len(parsed) < 3 {
+					log.Print("Got error: " + "Unsupported query")
+					break
+				}
+				switch parsed[1] {
+				case "id":
+					id, err := strconv.ParseInt(parsed[2], 10, 64)
+					if err == nil {
+						user.Id = id
+					} else {
+						log.Print("Got error: " + "Unable to parse user id")
+					}
+				case "login":
+					user.Login = parsed[2]
+				case "email":
+					reMail := regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+					if reMail.MatchString(parsed[2]) {
+						user.Email = parsed[2]
+					} else {
+						log.Print("Got error: " + "Unable to parse email")
+					}
+				}
+			}
+		}
+
+		if user.Id > 0 || user.Login != "" || user.Email != "" {
+			_, err := client.GetUser(c.Request().Context(), user)
+			if errors.Is(err, grafana.ErrNotFound) {
+				*user = grafana.User{}
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+		}
+	}
+
+	// setUserDisabled returns the handler disabling or enabling the resolved user
+	setUserDisabled := func(isDisabled bool) func(c flamego.Context, client *grafana.Client, user *grafana.User) string {
+		return func(c flamego.Context, client *grafana.Client, user *grafana.User) string {
+			if user.Id == 0 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+
+			status, err := client.SetUserDisabled(c.Request().Context(), user, isDisabled)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		}
+	}
+
 	f.Group("/users", func() {
-		var user grafana.User
-		f.Combo("/", func(c flamego.Context) {
-			user = grafana.User{}
+		f.Combo("/", func(c flamego.Context, client *grafana.Client) {
+			user := &grafana.User{}
+			c.Map(user)
 			user.Id = c.QueryInt64("id")
 			user.Login = c.QueryTrim("login")
 			user.Email = c.QueryTrim("email")
 
 			if user.Id > 0 || user.Login != "" || user.Email != "" {
-				_, err := grafana.GetUser(&user)
-				if err != nil && err.Error() == "Empty result" {
-					user = grafana.User{}
+				_, err := client.GetUser(c.Request().Context(), user)
+				if errors.Is(err, grafana.ErrNotFound) {
+					*user = grafana.User{}
 					c.ResponseWriter().WriteHeader(http.StatusNotFound)
 				} else if err != nil {
 					log.Print("Got error: " + err.Error())
-					c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+					c.ResponseWriter().WriteHeader(errorStatus(err))
 				}
 			}
-		}).Get(func(c flamego.Context) string {
+		}).Get(func(c flamego.Context, client *grafana.Client, user *grafana.User) string {
 			if user.Id > 0 {
 				jsonResponse, err := json.Marshal(user)
 				if err != nil {
 					log.Print("Got error: " + err.Error())
-					c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+					c.ResponseWriter().WriteHeader(errorStatus(err))
 					return "null"
 				}
 				c.ResponseWriter().Header().Add("Content-Type", "application/json")
 				return string(jsonResponse)
 			}
 
-			c.ResponseWriter().WriteHeader(http.StatusNoContent)
-			return "null"
-		}).Delete(func(c flamego.Context) string {
+			return searchUsers(c, client, "")
+		}).Delete(func(c flamego.Context, client *grafana.Client, user *grafana.User) string {
 			if user.Id == 0 {
 				c.ResponseWriter().WriteHeader(http.StatusNotFound)
 				return "false"
 			}
 
-			status, err := grafana.DeleteUser(&user)
+			status, err := client.DeleteUser(c.Request().Context(), user)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 			}
-			fmt.Printf("Results: %v\n", status)
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return strconv.FormatBool(status)
-		}).Post(func(c flamego.Context) string {
+		}).Post(func(c flamego.Context, client *grafana.Client, user *grafana.User) string {
 			requestBody, err := c.Request().Body().Bytes()
 			if err != nil {
 				log.Print("Got error: " + err.Error())
 			}
 
-			err = json.Unmarshal(requestBody, &user)
+			err = json.Unmarshal(requestBody, user)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
 			}
@@ -107,9 +326,11 @@ func Start() {
 				user.Password = util.RandString(12)
 			}
 
-			_, err = grafana.CreateUser(&user)
+			_, err = client.CreateUser(c.Request().Context(), user)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
 			}
 
 			if user.Login == "" {
@@ -123,51 +344,7 @@ func Start() {
 
 			return string(result) //strconv.FormatBool(user.Id > 0);
 		})
-		f.Combo("/{id}", func(c flamego.Context) {
-			user = grafana.User{}
-			id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
-			if id > 0 {
-				user.Id = id
-			} else {
-				r := regexp.MustCompile(`(?m)^(id|login|email)=([\p{L}\d_!-\.@]+)$`)
-				for _, s := range strings.Split(c.Param("id"), ",") {
-					parsed := r.FindStringSubmatch(s)
-					if parsed == nil || len(parsed) < 3 {
-						log.Print("Got error: " + "Unsupported query")
-						break
-					}
-					switch parsed[1] {
-					case "id":
-						id, err := strconv.ParseInt(parsed[2], 10, 64)
-						if err == nil {
-							user.Id = id
-						} else {
-							log.Print("Got error: " + "Unable to parse user id")
-						}
-					case "login":
-						user.Login = parsed[2]
-					case "email":
-						reMail := regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
-						if reMail.MatchString(parsed[2]) {
-							user.Email = parsed[2]
-						} else {
-							log.Print("Got error: " + "Unable to parse email")
-						}
-					}
-				}
-			}
-
-			if user.Id > 0 || user.Login != "" || user.Email != "" {
-				_, err := grafana.GetUser(&user)
-				if err != nil && err.Error() == "Empty result" {
-					user = grafana.User{}
-					c.ResponseWriter().WriteHeader(http.StatusNotFound)
-				} else if err != nil {
-					log.Print("Got error: " + err.Error())
-					c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-				}
-			}
-		}).Get(func(c flamego.Context) string {
+		f.Combo("/{id}", withUser).Get(func(c flamego.Context, client *grafana.Client, user *grafana.User) string {
 			if user.Id == 0 {
 				c.ResponseWriter().WriteHeader(http.StatusNotFound)
 				return ""
@@ -175,212 +352,503 @@ func Start() {
 			jsonResponse, err := json.Marshal(user)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 				return ""
 			}
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return string(jsonResponse)
-		}).Delete(func(c flamego.Context) string {
+		}).Delete(func(c flamego.Context, client *grafana.Client, user *grafana.User) string {
 			if user.Id == 0 {
 				c.ResponseWriter().WriteHeader(http.StatusNotFound)
 				return "false"
 			}
 
-			status, err := grafana.DeleteUser(&user)
+			status, err := client.DeleteUser(c.Request().Context(), user)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 			}
-			fmt.Printf("Results: %v\n", status)
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return strconv.FormatBool(status)
-		})
-	})
-	f.Get("/users/search/{slug}", func(c flamego.Context) string {
-		res, err := grafana.SearchUsers(c.Param("query"))
-		if err != nil {
-			log.Print("Got error: " + err.Error())
-		}
-		jsonResponse, err := json.Marshal(res)
-		if err != nil {
-			log.Print("Got error: " + err.Error())
-		}
-		c.ResponseWriter().Header().Add("Content-Type", "application/json")
-		return string(jsonResponse)
-	})
-	f.Patch("/users/organizations/", func(c flamego.Context) string {
-		requestBody, err := c.Request().Body().Bytes()
-		if err != nil {
-			log.Print("Got error: " + err.Error())
-		}
-
-		var userOrganizationsRequest struct {
-			User          grafana.User
-			Organizations []grafana.UserOrganization
-		}
-
-		err = json.Unmarshal(requestBody, &userOrganizationsRequest)
-		if err != nil {
-			log.Print("Got error: " + err.Error())
-		}
-
-		_, err = grafana.GetUser(&userOrganizationsRequest.User)
-		if err != nil {
-			log.Print("Got error: " + err.Error())
-			return "false"
-		}
-
-		_, err = grafana.SetUserOrganizations(&userOrganizationsRequest.User, &userOrganizationsRequest.Organizations)
-		if err != nil {
-			log.Print("Got error: " + err.Error())
-		}
-
-		result, err := json.Marshal(userOrganizationsRequest.User)
-		if err != nil {
-			log.Print("Got error: " + err.Error())
-		}
+		}).Put(func(c flamego.Context, client *grafana.Client, user *grafana.User) string {
+			if user.Id == 0 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
 
-		return string(result)
-	})
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
 
-	/*
-	   - ORGANIZATIONS -
-	   Retieving all organizations:
-	   GET
-	   .../organizations
+			// only the profile (login, email, name, theme) is updated here, the password,
+			// the server admin flag and the disabled state have their own routes
+			id := user.Id
+			err = json.Unmarshal(requestBody, user)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+			user.Id = id
+			user.Password = ""
 
-	   Retieving | Deleting single organization:
-	   GET | DELETE
-	   .../organizations/{id*} (.../organizations/1 || .../organizations/test)
-	   .../organizations/?id=1
-	   .../organizations/?name=test
+			_, err = client.UpdateUser(c.Request().Context(), user)
+			if err == nil {
+				*user = grafana.User{Id: id}
+				_, err = client.GetUser(c.Request().Context(), user)
+			}
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
 
-	   Creating organization:
-	   POST
-	   .../organizations/ (data: {})
-	*/
-	f.Group("/organizations", func() {
-		var organization grafana.Organization
-		f.Combo("/", func(c flamego.Context) {
-			organization = grafana.Organization{}
-			organization.Id = c.QueryInt64("id")
-			organization.Name = c.QueryTrim("name")
+			jsonResponse, err := json.Marshal(user)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		})
+		f.Post("/{id}/password", withUser, func(c flamego.Context, client *grafana.Client, user *grafana.User) string {
+			if user.Id == 0 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
 
-			if organization.Id > 0 || organization.Name != "" {
-				_, err := grafana.GetOrganization(&organization)
-				if err != nil && err.Error() == "Empty result" {
-					organization = grafana.Organization{}
-					c.ResponseWriter().WriteHeader(http.StatusNotFound)
-				} else if err != nil {
-					log.Print("Got error: " + err.Error())
-					c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-				}
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
 			}
 
-		}).Get(func(c flamego.Context) string {
-			if organization.Id > 0 {
-				jsonResponse, err := json.Marshal(organization)
+			var passwordRequest struct {
+				Password string `json:"password"`
+			}
+			if len(requestBody) > 0 {
+				err = json.Unmarshal(requestBody, &passwordRequest)
 				if err != nil {
-					log.Print("Got error: " + err.Error())
-					c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-					return "null"
+					c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+					return "false"
 				}
-				c.ResponseWriter().Header().Add("Content-Type", "application/json")
-				return string(jsonResponse)
 			}
 
-			var organizations []grafana.Organization
+			// the password is only answered back when it has been generated
+			generated := passwordRequest.Password == ""
+			user.Password = passwordRequest.Password
+			if generated {
+				user.Password = util.RandString(12)
+			}
 
-			organizations, err := grafana.GetOrganizations()
+			_, err = client.UpdateUserPassword(c.Request().Context(), user)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
 			}
-			jsonResponse, err := json.Marshal(organizations)
+			if !generated {
+				user.Password = ""
+			}
+
+			jsonResponse, err := json.Marshal(user)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-				return "null"
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
 			}
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return string(jsonResponse)
-		}).Delete(func(c flamego.Context) string {
-			if organization.Id == 0 {
+		})
+		f.Post("/{id}/disable", withUser, setUserDisabled(true))
+		f.Post("/{id}/enable", withUser, setUserDisabled(false))
+		f.Put("/{id}/admin", withUser, func(c flamego.Context, client *grafana.Client, user *grafana.User) string {
+			if user.Id == 0 {
 				c.ResponseWriter().WriteHeader(http.StatusNotFound)
 				return "false"
 			}
 
-			status, err := grafana.DeleteOrganization(&organization)
-			if err != nil {
-				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-			}
-			fmt.Printf("Results: %v\n", status)
-			c.ResponseWriter().Header().Add("Content-Type", "application/json")
-			return strconv.FormatBool(status)
-		}).Post(func(c flamego.Context) string {
 			requestBody, err := c.Request().Body().Bytes()
 			if err != nil {
 				log.Print("Got error: " + err.Error())
 			}
 
-			err = json.Unmarshal(requestBody, &organization)
+			var adminRequest struct {
+				IsGrafanaAdmin *bool `json:"isGrafanaAdmin"`
+			}
+			err = json.Unmarshal(requestBody, &adminRequest)
+			if err != nil || adminRequest.IsGrafanaAdmin == nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			status, err := client.SetUserGrafanaAdmin(c.Request().Context(), user, *adminRequest.IsGrafanaAdmin)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+		f.Get("/{id}/organizations", withUser, func(c flamego.Context, client *grafana.Client, user *grafana.User) string {
+			if user.Id == 0 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return ""
 			}
 
-			_, err = grafana.CreateOrganization(&organization)
+			organizations, err := client.GetOrganizationsByUser(c.Request().Context(), user)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
 			}
 
-			result, err := json.Marshal(organization)
+			jsonResponse, err := json.Marshal(organizations)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
 			}
-
-			return string(result)
+			c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(*organizations)))
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
 		})
 
-		getOrganization := func(uid string) (grafana.Organization, error) {
-			organization = grafana.Organization{}
-			id, _ := strconv.ParseInt(uid, 10, 64)
-			if id > 0 {
-				organization.Id = id
-				_, err := grafana.GetOrganization(&organization)
+		// withUserOrganization resolves the {org} organization of the user routes by id or name
+		withUserOrganization := func(c flamego.Context, client *grafana.Client) {
+			organization, err := getOrganization(c.Request().Context(), client, c.Param("org"))
+			c.Map(&organization)
+			if errors.Is(err, grafana.ErrNotFound) {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+		}
+
+		f.Combo("/{id}/organizations/{org}", withUser, withUserOrganization).Post(func(c flamego.Context, client *grafana.Client, user *grafana.User, organization *grafana.Organization) string {
+			if user.Id == 0 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			roleRequest := struct {
+				Role string `json:"role"`
+			}{Role: grafana.RoleViewer}
+			if len(requestBody) > 0 {
+				err = json.Unmarshal(requestBody, &roleRequest)
 				if err != nil {
-					return grafana.Organization{}, err
+					c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+					return "false"
 				}
 			}
 
-			if len(uid) > 0 && organization.Name == "" {
-				reName := regexp.MustCompile(`^([\p{L}\d\s_!-\.@|\]\[\(\)]+)*$`)
-				if reName.MatchString(uid) {
-					organization.Name = uid
-				} else {
-					return grafana.Organization{}, errors.New("Unable to parse organization name")
+			// a member is refused with 409, its role is changed by .../organizations/{orgId}/users/{user}
+			status, err := client.AddUserToOrganization(c.Request().Context(), user, organization, roleRequest.Role)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		}).Delete(func(c flamego.Context, client *grafana.Client, access *organizationAccess, user *grafana.User, organization *grafana.Organization) string {
+			if user.Id == 0 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+
+			status, err := client.DeleteUserFromOrganization(c.Request().Context(), user, organization)
+			if errors.Is(err, grafana.ErrLastAdmin) {
+				c.ResponseWriter().WriteHeader(http.StatusConflict)
+				return grafana.ErrLastAdmin.Error()
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+			if status && strings.EqualFold(user.Login, access.login) {
+				access.forget(*organization)
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+		f.Put("/{id}/current-organization", withUser, func(c flamego.Context, client *grafana.Client, user *grafana.User) string {
+			if user.Id == 0 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			var userOrganization grafana.UserOrganization
+			err = json.Unmarshal(requestBody, &userOrganization)
+			if err != nil || (userOrganization.Id == 0 && userOrganization.Name == "") {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			key := userOrganization.Name
+			if userOrganization.Id > 0 {
+				key = strconv.FormatInt(userOrganization.Id, 10)
+			}
+			organization, err := getOrganization(c.Request().Context(), client, key)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			// Grafana only switches to an organization the user is a member of
+			organizations, err := client.GetOrganizationsByUser(c.Request().Context(), user)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+			member := false
+			for _, userOrganization := range *organizations {
+				member = member || userOrganization.Id == organization.Id
+			}
+			if !member {
+				c.ResponseWriter().WriteHeader(http.StatusConflict)
+				return "User " + user.Login + " is not a member of organization " + organization.Name
+			}
+
+			_, err = client.SwitchCurrentOrganizationForUser(c.Request().Context(), user, int(organization.Id))
+			if err == nil {
+				*user = grafana.User{Id: user.Id}
+				_, err = client.GetUser(c.Request().Context(), user)
+			}
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			jsonResponse, err := json.Marshal(user)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		})
+	})
+	f.Get("/users/search/{slug}", func(c flamego.Context, client *grafana.Client) string {
+		return searchUsers(c, client, c.Param("slug"))
+	})
+	f.Patch("/users/organizations/", func(c flamego.Context, client *grafana.Client, access *organizationAccess) string {
+		requestBody, err := c.Request().Body().Bytes()
+		if err != nil {
+			log.Print("Got error: " + err.Error())
+		}
+
+		var userOrganizationsRequest struct {
+			User          grafana.User
+			Organizations []grafana.UserOrganization
+			Mode          string `json:"mode"`
+			ManagedPrefix string `json:"managedPrefix"`
+		}
+
+		err = json.Unmarshal(requestBody, &userOrganizationsRequest)
+		if err != nil {
+			log.Print("Got error: " + err.Error())
+		}
+
+		// the request may narrow the organizations the adapter owns, not widen them
+		options := grafana.UserOrganizationsOptions{
+			Mode:          userOrganizationsRequest.Mode,
+			ManagedPrefix: access.managedPrefix,
+		}
+		if userOrganizationsRequest.ManagedPrefix != "" {
+			if !strings.HasPrefix(userOrganizationsRequest.ManagedPrefix, access.managedPrefix) {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+			options.ManagedPrefix = userOrganizationsRequest.ManagedPrefix
+		}
+
+		_, err = client.GetUser(c.Request().Context(), &userOrganizationsRequest.User)
+		if err != nil {
+			log.Print("Got error: " + err.Error())
+			c.ResponseWriter().WriteHeader(errorStatus(err))
+			return "false"
+		}
+
+		// a failed change undoes the ones made before, the changes are reported either way
+		changes, changeErr := client.SetUserOrganizationsWith(c.Request().Context(), &userOrganizationsRequest.User, &userOrganizationsRequest.Organizations, options)
+		if changeErr != nil {
+			log.Print("Got error: " + changeErr.Error())
+			if changes == nil {
+				c.ResponseWriter().WriteHeader(errorStatus(changeErr))
+				return "false"
+			}
+		}
+
+		result, err := json.Marshal(struct {
+			User          grafana.User                     `json:"user"`
+			Organizations []grafana.UserOrganizationChange `json:"organizations"`
+		}{userOrganizationsRequest.User, changes})
+		if err != nil {
+			log.Print("Got error: " + err.Error())
+			c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+			return "false"
+		}
+		c.ResponseWriter().Header().Add("Content-Type", "application/json")
+		c.ResponseWriter().WriteHeader(errorStatus(changeErr))
+		return string(result)
+	})
+
+	/*
+	   - ORGANIZATIONS -
+	   Retieving all organizations:
+	   GET
+	   .../organizations
+
+	   Retieving | Deleting single organization:
+	   GET | DELETE
+	   .../organizations/{id*} (.../organizations/1 || .../organizations/test)
+	   .../organizations/?id=1
+	   .../organizations/?name=test
+
+	   Creating organization:
+	   POST
+	   .../organizations/ (data: {})
+
+	   Retrieving members of organization (role: Admin | Editor | Viewer | None, comma separated):
+	   GET
+	   .../organizations/{orgId}/users (.../organizations/11/users?role=Admin,Editor)
+
+	   Changing role | Removing member of organization (user by id, login or email):
+	   PUT | DELETE
+	   .../organizations/{orgId}/users/{user} (data: {"role": "Editor"})
+
+	   Replacing members of organization, the missing users are added and the unlisted members removed (an empty list
+	   only with allowEmpty=true):
+	   PUT
+	   .../organizations/{orgId}/users (data: [{"login": "test", "role": "Admin"}, {"email": "test2@test.test"}] || .../organizations/{orgId}/users?allowEmpty=true, data: [])
+	*/
+	f.Group("/organizations", func() {
+		f.Combo("/", func(c flamego.Context, client *grafana.Client) {
+			organization := &grafana.Organization{}
+			c.Map(organization)
+			organization.Id = c.QueryInt64("id")
+			organization.Name = c.QueryTrim("name")
+
+			if organization.Id > 0 || organization.Name != "" {
+				_, err := client.GetOrganization(c.Request().Context(), organization)
+				if errors.Is(err, grafana.ErrNotFound) {
+					*organization = grafana.Organization{}
+					c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				} else if err != nil {
+					log.Print("Got error: " + err.Error())
+					c.ResponseWriter().WriteHeader(errorStatus(err))
 				}
+			}
 
-				_, err := grafana.GetOrganization(&organization)
+		}).Get(func(c flamego.Context, client *grafana.Client, organization *grafana.Organization) string {
+			if organization.Id > 0 {
+				jsonResponse, err := json.Marshal(organization)
 				if err != nil {
-					return grafana.Organization{}, err
+					log.Print("Got error: " + err.Error())
+					c.ResponseWriter().WriteHeader(errorStatus(err))
+					return "null"
 				}
+				c.ResponseWriter().Header().Add("Content-Type", "application/json")
+				return string(jsonResponse)
 			}
 
-			return organization, nil
-		}
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
 
-		f.Combo("/{id}", func(c flamego.Context) {
-			var err error
-			organization, err = getOrganization(c.Param("id"))
-			if err != nil && err.Error() == "Empty result" {
-				organization = grafana.Organization{}
+			var organizations []grafana.Organization
+			if paged {
+				organizations, err = client.GetOrganizationsPage(c.Request().Context(), page)
+				if err == nil {
+					writePageHeaders(c, page, len(organizations), -1)
+				}
+			} else {
+				organizations, err = client.GetOrganizations(c.Request().Context())
+				if err == nil {
+					c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(organizations)))
+				}
+			}
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "null"
+			}
+			jsonResponse, err := json.Marshal(organizations)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "null"
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Delete(func(c flamego.Context, client *grafana.Client, organization *grafana.Organization) string {
+			if organization.Id == 0 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+
+			status, err := client.DeleteOrganization(c.Request().Context(), organization)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		}).Post(func(c flamego.Context, client *grafana.Client, organization *grafana.Organization) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			err = json.Unmarshal(requestBody, organization)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			_, err = client.CreateOrganization(c.Request().Context(), organization)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(organization)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			return string(result)
+		})
+
+		f.Combo("/{id}", func(c flamego.Context, client *grafana.Client) {
+			organization, err := getOrganization(c.Request().Context(), client, c.Param("id"))
+			c.Map(&organization)
+			if errors.Is(err, grafana.ErrNotFound) {
 				c.ResponseWriter().WriteHeader(http.StatusNotFound)
 			} else if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 			}
-		}).Get(func(c flamego.Context) string {
+		}).Get(func(c flamego.Context, client *grafana.Client, organization *grafana.Organization) string {
 			if organization.Id == 0 {
 				c.ResponseWriter().WriteHeader(http.StatusNotFound)
 				return ""
@@ -388,225 +856,1990 @@ func Start() {
 			jsonResponse, err := json.Marshal(organization)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Delete(func(c flamego.Context, client *grafana.Client, organization *grafana.Organization) string {
+			if organization.Id == 0 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+
+			status, err := client.DeleteOrganization(c.Request().Context(), organization)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+
+		// withOrganizationOnly resolves the {orgId} organization for the routes managing the
+		// organization itself, without joining it
+		withOrganizationOnly := func(c flamego.Context, client *grafana.Client) {
+			organization, err := getOrganization(c.Request().Context(), client, c.Param("orgId"))
+			c.Map(&organization)
+			if errors.Is(err, grafana.ErrNotFound) {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+		}
+
+		// withMember resolves the {user} member of the organization by id, login or email,
+		// the id=, login= and email= selectors included
+		withMember := func(c flamego.Context, client *grafana.Client, organization *grafana.Organization) {
+			member := &grafana.OrganizationUser{}
+			c.Map(member)
+
+			members, err := client.GetUsersInOrganization(c.Request().Context(), organization)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return
+			}
+
+			key := regexp.MustCompile(`^(id|login|email)=`).ReplaceAllString(c.Param("user"), "")
+			id, _ := strconv.ParseInt(key, 10, 64)
+			for _, m := range *members {
+				if (id > 0 && m.Id == id) || strings.EqualFold(m.Login, key) || strings.EqualFold(m.Email, key) {
+					*member = m
+					return
+				}
+			}
+			c.ResponseWriter().WriteHeader(http.StatusNotFound)
+		}
+
+		f.Combo("/{orgId}/users", withOrganizationOnly).Get(func(c flamego.Context, client *grafana.Client, organization *grafana.Organization) string {
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
+
+			members, err := client.GetUsersInOrganization(c.Request().Context(), organization)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			roles := make(map[string]bool)
+			for _, role := range strings.Split(c.QueryTrim("role"), ",") {
+				if strings.TrimSpace(role) != "" {
+					roles[strings.ToLower(strings.TrimSpace(role))] = true
+				}
+			}
+			users := make([]grafana.OrganizationUser, 0, len(*members))
+			for _, member := range *members {
+				if len(roles) == 0 || roles[strings.ToLower(member.Role)] {
+					users = append(users, member)
+				}
+			}
+
+			if paged {
+				start, end := pageBounds(len(users), page)
+				writePageHeaders(c, page, end-start, int64(len(users)))
+				users = users[start:end]
+			} else {
+				c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(users)))
+			}
+
+			jsonResponse, err := json.Marshal(users)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Put(func(c flamego.Context, client *grafana.Client, access *organizationAccess, organization *grafana.Organization) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			var users []grafana.OrganizationUser
+			err = json.Unmarshal(requestBody, &users)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			// an empty or missing list would remove every member, it has to be asked for
+			options := grafana.OrganizationUsersOptions{AllowEmpty: c.QueryBool("allowEmpty")}
+			if len(users) == 0 && !options.AllowEmpty {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			// the admin user the adapter joins the organizations with stays a member
+			if access.mode == settings.OrgAccessHeader && !client.UsesToken() {
+				members, err := client.GetUsersInOrganization(c.Request().Context(), organization)
+				if err != nil {
+					log.Print("Got error: " + err.Error())
+					c.ResponseWriter().WriteHeader(errorStatus(err))
+					return "false"
+				}
+				for _, member := range *members {
+					if !strings.EqualFold(member.Login, access.login) {
+						continue
+					}
+					listed := false
+					for _, user := range users {
+						listed = listed || user.Id == member.Id || strings.EqualFold(user.Login, member.Login) || (user.Email != "" && strings.EqualFold(user.Email, member.Email))
+					}
+					if !listed {
+						users = append(users, member)
+					}
+				}
+			}
+
+			changes, err := client.SetOrganizationUsersWith(c.Request().Context(), organization, users, options)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			status := http.StatusOK
+			for _, change := range changes {
+				if change.Error != "" {
+					status = http.StatusMultiStatus
+				}
+				if strings.EqualFold(change.Login, access.login) {
+					access.forget(*organization)
+				}
+			}
+
+			jsonResponse, err := json.Marshal(changes)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				return "false"
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			c.ResponseWriter().WriteHeader(status)
+			return string(jsonResponse)
+		})
+		f.Combo("/{orgId}/users/{user}", withOrganizationOnly, withMember).Get(func(c flamego.Context, member *grafana.OrganizationUser) string {
+			jsonResponse, err := json.Marshal(member)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Put(func(c flamego.Context, client *grafana.Client, access *organizationAccess, organization *grafana.Organization, member *grafana.OrganizationUser) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			var roleRequest struct {
+				Role string `json:"role"`
+			}
+			err = json.Unmarshal(requestBody, &roleRequest)
+			if err != nil || roleRequest.Role == "" {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			status, err := client.UpdateUserInOrganization(c.Request().Context(), &grafana.User{Id: member.Id}, organization, roleRequest.Role)
+			if errors.Is(err, grafana.ErrLastAdmin) {
+				c.ResponseWriter().WriteHeader(http.StatusConflict)
+				return grafana.ErrLastAdmin.Error()
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+			if status && strings.EqualFold(member.Login, access.login) {
+				access.forget(*organization)
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		}).Delete(func(c flamego.Context, client *grafana.Client, access *organizationAccess, organization *grafana.Organization, member *grafana.OrganizationUser) string {
+			status, err := client.DeleteUserFromOrganization(c.Request().Context(), &grafana.User{Id: member.Id}, organization)
+			if errors.Is(err, grafana.ErrLastAdmin) {
+				c.ResponseWriter().WriteHeader(http.StatusConflict)
+				return grafana.ErrLastAdmin.Error()
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+			if status && strings.EqualFold(member.Login, access.login) {
+				access.forget(*organization)
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+
+		// withOrganization resolves the {orgId} organization and maps it along with the client
+		// scoped to it for the handlers that follow
+		withOrganization := func(c flamego.Context, client *grafana.Client, access *organizationAccess) {
+			organization, err := getOrganization(c.Request().Context(), client, c.Param("orgId"))
+			c.Map(&organization)
+			if errors.Is(err, grafana.ErrNotFound) {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return
+			}
+
+			orgClient, err := access.client(c.Request().Context(), client, organization)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return
+			}
+			c.Map(organizationClient{orgClient})
+		}
+
+		// getFolder looks the folder up by id, uid or title
+		getFolder := func(ctx context.Context, orgClient organizationClient, key string) (grafana.Folder, error) {
+			folder := grafana.Folder{}
+			id, _ := strconv.ParseInt(key, 10, 64)
+			if id > 0 {
+				folder.Id = id
+				_, err := orgClient.GetFolderById(ctx, &folder)
+				if err == nil {
+					return folder, nil
+				} else if !errors.Is(err, grafana.ErrNotFound) {
+					return grafana.Folder{}, err
+				}
+			}
+
+			folder = grafana.Folder{Uid: key}
+			_, err := orgClient.GetFolder(ctx, &folder)
+			if err == nil {
+				return folder, nil
+			} else if !errors.Is(err, grafana.ErrNotFound) {
+				return grafana.Folder{}, err
+			}
+
+			folder = grafana.Folder{Title: key}
+			_, err = orgClient.GetFolderByTitle(ctx, &folder)
+			if err != nil {
+				return grafana.Folder{}, err
+			}
+
+			return folder, nil
+		}
+
+		/*
+		   - DASHBOARDS FOR ORGANIZATION -
+
+		   Retieving:
+		   GET
+		   .../organizations/{orgId}/dashboards/ (.../organizations/11/dashboards/ || .../organizations/test/dashboards/)
+
+		   Searching (folder by id, uid or title, tags all have to match, sort alpha-asc | alpha-desc):
+		   GET
+		   .../organizations/{orgId}/dashboards/?query=cpu&tag=prod&tag=linux&folder=ops&starred=true&sort=alpha-desc
+
+		   Retieving | Deleting single dashboard:
+		   GET | DELETE
+		   .../organizations/{orgId}/dashboards/{uid} (.../organizations/11/dashboards/GPXicXZRk || .../organizations/test/dashboards/organization%20title || .../organizations/test/dashboards/23)
+
+		   Creating dashboard:
+		   POST
+		   .../organizations/{orgId}/dashboards/ (data: {})
+
+		   Retrieving | Replacing permissions of dashboard:
+		   GET | PUT
+		   .../organizations/{orgId}/dashboards/{uid}/permissions (data: [{"userLogin": "test", "permissionName": "Edit"}, {"team": "ops", "permission": 4}, {"role": "Viewer", "permission": 1}])
+
+		   Retrieving all versions | single version of dashboard:
+		   GET
+		   .../organizations/{orgId}/dashboards/{uid}/versions (.../organizations/11/dashboards/GPXicXZRk/versions || .../organizations/11/dashboards/GPXicXZRk/versions/3)
+
+		   Comparing two versions of dashboard (to is the current version by default):
+		   GET
+		   .../organizations/{orgId}/dashboards/{uid}/diff?from=2&to=5
+
+		   Restoring version of dashboard:
+		   POST
+		   .../organizations/{orgId}/dashboards/{uid}/versions/{version}/restore (.../organizations/11/dashboards/GPXicXZRk/versions/3/restore)
+		*/
+
+		f.Combo("/{orgId}/dashboards/", withOrganization).Get(func(c flamego.Context, orgClient organizationClient) string {
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
+
+			search := grafana.DashboardSearch{
+				Query:   c.QueryTrim("query"),
+				Starred: c.QueryBool("starred"),
+				Sort:    c.QueryTrim("sort"),
+			}
+			for _, tags := range c.Request().URL.Query()["tag"] {
+				for _, tag := range strings.Split(tags, ",") {
+					if strings.TrimSpace(tag) != "" {
+						search.Tags = append(search.Tags, strings.TrimSpace(tag))
+					}
+				}
+			}
+			if c.QueryTrim("folder") != "" {
+				folder, err := getFolder(c.Request().Context(), orgClient, c.QueryTrim("folder"))
+				if errors.Is(err, grafana.ErrNotFound) {
+					c.ResponseWriter().WriteHeader(http.StatusNotFound)
+					return "No folder " + c.QueryTrim("folder")
+				} else if err != nil {
+					log.Print("Got error: " + err.Error())
+					c.ResponseWriter().WriteHeader(errorStatus(err))
+					return ""
+				}
+				search.FolderUids = []string{folder.Uid}
+			}
+
+			var hits []grafana.SearchHit
+			if paged {
+				hits, err = orgClient.SearchDashboardsPage(c.Request().Context(), search, page)
+				if err == nil {
+					writePageHeaders(c, page, len(hits), -1)
+				}
+			} else {
+				hits, err = orgClient.SearchDashboards(c.Request().Context(), search)
+				if err == nil {
+					c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(hits)))
+				}
+			}
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			dashboards := make([]grafana.Dashboard, 0, len(hits))
+			for _, hit := range hits {
+				dashboards = append(dashboards, hit.Dashboard())
+			}
+
+			jsonResponse, err := json.Marshal(dashboards)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Post(func(c flamego.Context, orgClient organizationClient) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+			var dashboard grafana.Dashboard
+			_, err = orgClient.GetDashboard(c.Request().Context(), &dashboard)
+			if errors.Is(err, grafana.ErrNotFound) {
+				dashboard = grafana.Dashboard{}
+			}
+			err = json.Unmarshal(requestBody, &dashboard)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			// overwrite is opt-in, Grafana refuses with 412 to update a dashboard saved
+			// since the version sent, the one required by If-Match when it is given
+			if c.Request().Header.Get("If-Match") != "" {
+				var err error
+				current := grafana.Dashboard{Dashboard: grafana.DashboardModel{Id: dashboard.Dashboard.Id, Uid: dashboard.Dashboard.Uid}}
+				if current.Dashboard.Uid == "" && current.Dashboard.Id > 0 {
+					_, err = orgClient.GetDashboard(c.Request().Context(), &current)
+				}
+				if err == nil && current.Dashboard.Uid != "" {
+					_, err = orgClient.GetDashboardByUid(c.Request().Context(), &current)
+				}
+				if errors.Is(err, grafana.ErrNotFound) || current.Dashboard.Uid == "" {
+					c.ResponseWriter().WriteHeader(http.StatusPreconditionFailed)
+					return "false"
+				} else if err != nil {
+					log.Print("Got error: " + err.Error())
+					c.ResponseWriter().WriteHeader(errorStatus(err))
+					return "false"
+				}
+				if preconditionFailed(c, current.Dashboard.Version) {
+					return "false"
+				}
+				dashboard.Dashboard.Version = current.Dashboard.Version
+				dashboard.Overwrite = false
+			}
+
+			if len(dashboard.Message) == 0 {
+				dashboard.Message = "Grafana adapter update " + time.Now().Format("02-01-2006 15:04:05")
+			}
+			_, err = orgClient.UpdateDashboard(c.Request().Context(), &dashboard)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+			c.ResponseWriter().Header().Set("ETag", etag(dashboard.Dashboard.Version))
+
+			result, err := marshalUnescaped(dashboard)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			return string(result)
+		})
+		// withDashboard resolves the {uid} dashboard of the organization by id, title or uid
+		withDashboard := func(c flamego.Context, orgClient organizationClient) {
+			dashboard := &grafana.Dashboard{}
+			c.Map(dashboard)
+			uid := c.Param("uid")
+			id, _ := strconv.ParseInt(uid, 10, 64)
+			if id > 0 {
+				dashboard.Dashboard.Id = id
+				_, err := orgClient.GetDashboard(c.Request().Context(), dashboard)
+				if err != nil {
+					log.Print("Got error: " + err.Error())
+				}
+			}
+			if len(uid) > 0 && dashboard.Dashboard.Title == "" {
+				reName := regexp.MustCompile(`^([\p{L}\d\s_!-\.@|\]\[\(\)]+)*$`)
+				if reName.MatchString(uid) {
+					dashboard.Dashboard.Title = uid
+					_, err := orgClient.GetDashboard(c.Request().Context(), dashboard)
+					if err != nil {
+						log.Print("Got error: " + err.Error())
+					}
+				}
+			}
+			if len(uid) > 0 && dashboard.Dashboard.Uid == "" {
+				dashboard.Dashboard.Uid = uid
+			}
+
+			// search results only hold a summary, the whole dashboard JSON is loaded by uid
+			found := grafana.Dashboard{Dashboard: grafana.DashboardModel{Uid: dashboard.Dashboard.Uid}}
+			_, err := orgClient.GetDashboardByUid(c.Request().Context(), &found)
+			if errors.Is(err, grafana.ErrNotFound) {
+				*dashboard = grafana.Dashboard{}
+				return
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return
+			}
+			*dashboard = found
+		}
+
+		f.Combo("/{orgId}/dashboards/{uid}", withOrganization, withDashboard).Get(func(c flamego.Context, dashboard *grafana.Dashboard) string {
+			if dashboard.Dashboard.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return ""
+			}
+			jsonResponse, err := marshalUnescaped(dashboard)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Set("ETag", etag(dashboard.Dashboard.Version))
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Delete(func(c flamego.Context, orgClient organizationClient, dashboard *grafana.Dashboard) string {
+			if dashboard.Dashboard.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+			if preconditionFailed(c, dashboard.Dashboard.Version) {
+				return "false"
+			}
+
+			status, err := orgClient.DeleteDashboard(c.Request().Context(), dashboard)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+		f.Combo("/{orgId}/dashboards/{uid}/permissions", withOrganization, withDashboard).Get(func(c flamego.Context, orgClient organizationClient, dashboard *grafana.Dashboard) string {
+			if dashboard.Dashboard.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return ""
+			}
+
+			permissions, err := orgClient.GetDashboardPermissions(c.Request().Context(), dashboard)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			jsonResponse, err := json.Marshal(permissions)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Put(func(c flamego.Context, orgClient organizationClient, dashboard *grafana.Dashboard) string {
+			if dashboard.Dashboard.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			permissions := make([]grafana.Permission, 0)
+			err = json.Unmarshal(requestBody, &permissions)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			status, err := orgClient.SetDashboardPermissions(c.Request().Context(), dashboard, &permissions)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+
+		f.Get("/{orgId}/dashboards/{uid}/versions", withOrganization, withDashboard, func(c flamego.Context, orgClient organizationClient, dashboard *grafana.Dashboard) string {
+			if dashboard.Dashboard.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return ""
+			}
+
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
+
+			all, err := orgClient.GetDashboardVersions(c.Request().Context(), dashboard)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			versions := all
+			if paged {
+				start, end := pageBounds(len(versions), page)
+				versions = versions[start:end]
+				writePageHeaders(c, page, len(versions), int64(len(all)))
+			} else {
+				c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(versions)))
+			}
+
+			jsonResponse, err := json.Marshal(versions)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		})
+		f.Get("/{orgId}/dashboards/{uid}/versions/{version}", withOrganization, withDashboard, func(c flamego.Context, orgClient organizationClient, dashboard *grafana.Dashboard) string {
+			version, _ := strconv.Atoi(c.Param("version"))
+			if dashboard.Dashboard.Uid == "" || version < 1 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return ""
+			}
+
+			dashboardVersion, err := orgClient.GetDashboardVersion(c.Request().Context(), dashboard, version)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			jsonResponse, err := marshalUnescaped(dashboardVersion)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		})
+		f.Post("/{orgId}/dashboards/{uid}/versions/{version}/restore", withOrganization, withDashboard, func(c flamego.Context, orgClient organizationClient, dashboard *grafana.Dashboard) string {
+			version, _ := strconv.Atoi(c.Param("version"))
+			if dashboard.Dashboard.Uid == "" || version < 1 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+
+			_, err := orgClient.RestoreDashboardVersion(c.Request().Context(), dashboard, version)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			// the restored dashboard is answered as it has been saved
+			_, err = orgClient.GetDashboardByUid(c.Request().Context(), dashboard)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			result, err := marshalUnescaped(dashboard)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		})
+		f.Get("/{orgId}/dashboards/{uid}/diff", withOrganization, withDashboard, func(c flamego.Context, orgClient organizationClient, dashboard *grafana.Dashboard) string {
+			if dashboard.Dashboard.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return ""
+			}
+
+			// the current version is compared unless another one is given
+			from, to := c.QueryInt("from"), dashboard.Dashboard.Version
+			if c.QueryTrim("to") != "" {
+				to = c.QueryInt("to")
+			}
+			if from < 1 || to < 1 {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "The from and to query parameters must be dashboard versions"
+			}
+
+			changes, err := orgClient.DiffDashboardVersions(c.Request().Context(), dashboard, from, to)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			jsonResponse, err := marshalUnescaped(changes)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		})
+
+		/*
+		   - FOLDERS FOR ORGANIZATION -
+		   Retieving all folders:
+		   GET
+		   .../organizations/{orgId}/folders (.../organizations/11/dashboards/)
+
+		   Retieving | Updating single fodler:
+		   GET | PUT | DELETE
+		   .../organizations/{orgId}/folders/{id} (.../organizations/11/folders/nErXDvCkzz | .../organizations/11/folders/11)
+
+		   Creating folder:
+		   POST
+		   .../organizations/{orgId}/folders/ (data: {})
+
+		   Retrieving | Replacing permissions of folder:
+		   GET | PUT
+		   .../organizations/{orgId}/folders/{id}/permissions (data: [{"userLogin": "test", "permissionName": "Edit"}, {"team": "ops", "permission": 4}])
+		*/
+
+		f.Combo("/{orgId}/folders/", withOrganization).Get(func(c flamego.Context, orgClient organizationClient) string {
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
+
+			var folders []grafana.Folder
+			if paged {
+				folders, err = orgClient.GetFoldersPage(c.Request().Context(), page)
+				if err == nil {
+					writePageHeaders(c, page, len(folders), -1)
+				}
+			} else {
+				folders, err = orgClient.GetFolders(c.Request().Context())
+				if err == nil {
+					c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(folders)))
+				}
+			}
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			jsonResponse, err := json.Marshal(folders)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+
+		}).Post(func(c flamego.Context, orgClient organizationClient) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			var folder grafana.Folder
+
+			err = json.Unmarshal(requestBody, &folder)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			_, err = orgClient.CreateFolder(c.Request().Context(), &folder)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(folder)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			return string(result)
+		})
+		// withFolder resolves the {id} folder of the organization by id, uid or title
+		withFolder := func(c flamego.Context, orgClient organizationClient) {
+			folder, err := getFolder(c.Request().Context(), orgClient, c.Param("id"))
+			c.Map(&folder)
+			if errors.Is(err, grafana.ErrNotFound) {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+		}
+
+		f.Combo("/{orgId}/folders/{id}", withOrganization, withFolder).Get(func(c flamego.Context, folder *grafana.Folder) string {
+			if folder.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return ""
+			}
+			jsonResponse, err := json.Marshal(folder)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Set("ETag", etag(folder.Version))
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Put(func(c flamego.Context, orgClient organizationClient, folder *grafana.Folder) string {
+			if folder.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+			if preconditionFailed(c, folder.Version) {
+				return "false"
+			}
+
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			// Grafana refuses with 412 to update a folder saved since the version sent unless
+			// overwrite is set. The version matched by If-Match is sent when it is given, else
+			// the version of the data, else the current one
+			uid, version := folder.Uid, folder.Version
+			folder.Overwrite = false
+			err = json.Unmarshal(requestBody, folder)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+			folder.Uid = uid
+			if c.Request().Header.Get("If-Match") != "" {
+				folder.Version, folder.Overwrite = version, false
+			}
+
+			_, err = orgClient.UpdateFolder(c.Request().Context(), folder)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(folder)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			c.ResponseWriter().Header().Set("ETag", etag(folder.Version))
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		}).Delete(func(c flamego.Context, orgClient organizationClient, folder *grafana.Folder) string {
+			if folder.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+			if preconditionFailed(c, folder.Version) {
+				return "false"
+			}
+
+			status, err := orgClient.DeleteFolder(c.Request().Context(), folder)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+		f.Combo("/{orgId}/folders/{id}/permissions", withOrganization, withFolder).Get(func(c flamego.Context, orgClient organizationClient, folder *grafana.Folder) string {
+			if folder.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return ""
+			}
+
+			permissions, err := orgClient.GetFolderPermissions(c.Request().Context(), folder)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			jsonResponse, err := json.Marshal(permissions)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Put(func(c flamego.Context, orgClient organizationClient, folder *grafana.Folder) string {
+			if folder.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			permissions := make([]grafana.Permission, 0)
+			err = json.Unmarshal(requestBody, &permissions)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			status, err := orgClient.SetFolderPermissions(c.Request().Context(), folder, &permissions)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+
+		/*
+		   - DATASOURCES FOR ORGANIZATION -
+		   Retieving all datasources:
+		   GET
+		   .../organizations/{orgId}/datasources (.../organizations/11/datasources/)
+
+		   Retieving | Updating single datasource:
+		   GET | PUT | DELETE
+		   .../organizations/{orgId}/datasources/{id} (.../organizations/11/datasources/nErXDvCkzz | .../organizations/11/datasources/11)
+
+		   Creating datasource:
+		   POST
+		   .../organizations/{orgId}/datasources/ (data: {})
+		*/
+
+		f.Combo("/{orgId}/datasources/", withOrganization).Get(func(c flamego.Context, orgClient organizationClient) string {
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
+
+			all, err := orgClient.GetDatasources(c.Request().Context())
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			// Grafana lists all datasources at once
+			datasources := *all
+			if paged {
+				start, end := pageBounds(len(datasources), page)
+				datasources = datasources[start:end]
+				writePageHeaders(c, page, len(datasources), int64(len(*all)))
+			} else {
+				c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(datasources)))
+			}
+
+			jsonResponse, err := json.Marshal(datasources)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+
+		}).Post(func(c flamego.Context, orgClient organizationClient) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			var datasource grafana.Datasource
+
+			err = json.Unmarshal(requestBody, &datasource)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			_, err = orgClient.CreateDatasource(c.Request().Context(), &datasource)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(datasource)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			return string(result)
+		})
+		f.Combo("/{orgId}/datasources/{id}", withOrganization, func(c flamego.Context, orgClient organizationClient) {
+			datasource := &grafana.Datasource{}
+			c.Map(datasource)
+			var err error
+			id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+			name := c.Param("id")
+			if id > 0 {
+				datasource.Id = id
+				_, err = orgClient.GetDatasource(c.Request().Context(), datasource)
+			}
+
+			if len(name) > 0 && datasource.Uid == "" {
+				datasource.Name = name
+				_, err = orgClient.GetDatasource(c.Request().Context(), datasource)
+			}
+
+			if len(name) > 0 && datasource.Uid == "" {
+				datasource.Uid = name
+				_, err = orgClient.GetDatasource(c.Request().Context(), datasource)
+			}
+
+			if errors.Is(err, grafana.ErrNotFound) {
+				*datasource = grafana.Datasource{}
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+		}).Get(func(c flamego.Context, datasource *grafana.Datasource) string {
+			if datasource.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return ""
+			}
+			jsonResponse, err := json.Marshal(datasource)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Set("ETag", etag(datasource.Version))
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Put(func(c flamego.Context, orgClient organizationClient, datasource *grafana.Datasource) string {
+			if datasource.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+			if preconditionFailed(c, datasource.Version) {
+				return "false"
+			}
+
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			// Grafana refuses with 409 to update a datasource saved since the version sent, the
+			// version matched by If-Match when it is given, else the version of the data, else the
+			// current one. Version 0 is not checked
+			id, uid, version := datasource.Id, datasource.Uid, datasource.Version
+			err = json.Unmarshal(requestBody, datasource)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+			datasource.Id, datasource.Uid = id, uid
+			if c.Request().Header.Get("If-Match") != "" {
+				datasource.Version = version
+			}
+
+			_, err = orgClient.UpdateDatasource(c.Request().Context(), datasource)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(datasource)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			c.ResponseWriter().Header().Set("ETag", etag(datasource.Version))
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		}).Delete(func(c flamego.Context, orgClient organizationClient, datasource *grafana.Datasource) string {
+			if datasource.Uid == "" {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+			if preconditionFailed(c, datasource.Version) {
+				return "false"
+			}
+
+			status, err := orgClient.DeleteDatasource(c.Request().Context(), datasource)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+
+		/*
+		   - SERVICE ACCOUNTS FOR ORGANIZATION -
+		   Retrieving all service accounts:
+		   GET
+		   .../organizations/{orgId}/service-accounts/ (.../organizations/11/service-accounts/)
+
+		   Retrieving | Deleting single service account:
+		   GET | DELETE
+		   .../organizations/{orgId}/service-accounts/{id} (.../organizations/11/service-accounts/3)
+
+		   Creating service account:
+		   POST
+		   .../organizations/{orgId}/service-accounts/ (data: {"name": "ci", "role": "Editor"})
+
+		   Retrieving all tokens | Creating token (the key is only returned here, once):
+		   GET | POST
+		   .../organizations/{orgId}/service-accounts/{id}/tokens (data: {"name": "ci-token", "secondsToLive": 86400})
+
+		   Deleting token:
+		   DELETE
+		   .../organizations/{orgId}/service-accounts/{id}/tokens/{tokenId}
+		*/
+
+		f.Combo("/{orgId}/service-accounts/", withOrganization).Get(func(c flamego.Context, orgClient organizationClient) string {
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
+
+			var serviceAccounts []grafana.ServiceAccount
+			if paged {
+				var totalCount int64
+				serviceAccounts, totalCount, err = orgClient.GetServiceAccountsPage(c.Request().Context(), page)
+				if err == nil {
+					writePageHeaders(c, page, len(serviceAccounts), totalCount)
+				}
+			} else {
+				serviceAccounts, err = orgClient.GetServiceAccounts(c.Request().Context())
+				if err == nil {
+					c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(serviceAccounts)))
+				}
+			}
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			jsonResponse, err := json.Marshal(serviceAccounts)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Post(func(c flamego.Context, orgClient organizationClient) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			var serviceAccount grafana.ServiceAccount
+			err = json.Unmarshal(requestBody, &serviceAccount)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			_, err = orgClient.CreateServiceAccount(c.Request().Context(), &serviceAccount)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(serviceAccount)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		})
+
+		// withServiceAccount resolves the {id} service account of the organization
+		withServiceAccount := func(c flamego.Context, orgClient organizationClient) {
+			serviceAccount := &grafana.ServiceAccount{}
+			c.Map(serviceAccount)
+			serviceAccount.Id, _ = strconv.ParseInt(c.Param("id"), 10, 64)
+			if serviceAccount.Id == 0 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, err := orgClient.GetServiceAccount(c.Request().Context(), serviceAccount)
+			if errors.Is(err, grafana.ErrNotFound) {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+		}
+
+		f.Combo("/{orgId}/service-accounts/{id}", withOrganization, withServiceAccount).Get(func(c flamego.Context, serviceAccount *grafana.ServiceAccount) string {
+			jsonResponse, err := json.Marshal(serviceAccount)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Delete(func(c flamego.Context, orgClient organizationClient, serviceAccount *grafana.ServiceAccount) string {
+			status, err := orgClient.DeleteServiceAccount(c.Request().Context(), serviceAccount)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+		f.Combo("/{orgId}/service-accounts/{id}/tokens", withOrganization, withServiceAccount).Get(func(c flamego.Context, orgClient organizationClient, serviceAccount *grafana.ServiceAccount) string {
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
+
+			all, err := orgClient.GetServiceAccountTokens(c.Request().Context(), serviceAccount)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			// Grafana lists all tokens at once
+			tokens := all
+			if paged {
+				start, end := pageBounds(len(all), page)
+				tokens = all[start:end]
+				writePageHeaders(c, page, len(tokens), int64(len(all)))
+			} else {
+				c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(tokens)))
+			}
+
+			jsonResponse, err := json.Marshal(tokens)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Post(func(c flamego.Context, orgClient organizationClient, serviceAccount *grafana.ServiceAccount) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			var token grafana.ServiceAccountToken
+			err = json.Unmarshal(requestBody, &token)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			_, err = orgClient.CreateServiceAccountToken(c.Request().Context(), serviceAccount, &token)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(token)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		})
+		f.Delete("/{orgId}/service-accounts/{id}/tokens/{tokenId}", withOrganization, withServiceAccount, func(c flamego.Context, orgClient organizationClient, serviceAccount *grafana.ServiceAccount) string {
+			token := grafana.ServiceAccountToken{}
+			token.Id, _ = strconv.ParseInt(c.Param("tokenId"), 10, 64)
+			if token.Id == 0 {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return "false"
+			}
+
+			status, err := orgClient.DeleteServiceAccountToken(c.Request().Context(), serviceAccount, &token)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+
+		/*
+		   - TEAMS FOR ORGANIZATION -
+		   Retrieving all | matching teams:
+		   GET
+		   .../organizations/{orgId}/teams/ (.../organizations/11/teams/ || .../organizations/11/teams/?query=ops)
+
+		   Retrieving | Updating | Deleting single team:
+		   GET | PUT | DELETE
+		   .../organizations/{orgId}/teams/{id} (.../organizations/11/teams/4 || .../organizations/11/teams/ops)
+
+		   Creating team:
+		   POST
+		   .../organizations/{orgId}/teams/ (data: {"name": "ops", "email": "ops@localhost"})
+
+		   Retrieving members | Adding member | Replacing all members:
+		   GET | POST | PUT
+		   .../organizations/{orgId}/teams/{id}/members (data: {"login": "test", "permission": 4} | [{"userId": 2}, {"email": "test@test.test", "permission": 4}])
+
+		   Updating | Removing member:
+		   PATCH | DELETE
+		   .../organizations/{orgId}/teams/{id}/members/{userId} (data: {"permission": 4})
+		*/
+
+		f.Combo("/{orgId}/teams/", withOrganization).Get(func(c flamego.Context, orgClient organizationClient) string {
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
+
+			var teams []grafana.Team
+			if paged {
+				var totalCount int64
+				teams, totalCount, err = orgClient.SearchTeamsPage(c.Request().Context(), c.QueryTrim("query"), page)
+				if err == nil {
+					writePageHeaders(c, page, len(teams), totalCount)
+				}
+			} else {
+				teams, err = orgClient.SearchTeams(c.Request().Context(), c.QueryTrim("query"))
+				if err == nil {
+					c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(teams)))
+				}
+			}
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			jsonResponse, err := json.Marshal(teams)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Post(func(c flamego.Context, orgClient organizationClient) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			var team grafana.Team
+			err = json.Unmarshal(requestBody, &team)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			_, err = orgClient.CreateTeam(c.Request().Context(), &team)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(team)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		})
+
+		// withTeam resolves the {id} team of the organization by id or name
+		withTeam := func(c flamego.Context, orgClient organizationClient) {
+			team := &grafana.Team{}
+			c.Map(team)
+			team.Id, _ = strconv.ParseInt(c.Param("id"), 10, 64)
+			if team.Id == 0 {
+				team.Name = c.Param("id")
+			}
+
+			_, err := orgClient.GetTeam(c.Request().Context(), team)
+			if errors.Is(err, grafana.ErrNotFound) {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+		}
+
+		f.Combo("/{orgId}/teams/{id}", withOrganization, withTeam).Get(func(c flamego.Context, team *grafana.Team) string {
+			jsonResponse, err := json.Marshal(team)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Put(func(c flamego.Context, orgClient organizationClient, team *grafana.Team) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			id := team.Id
+			err = json.Unmarshal(requestBody, team)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+			team.Id = id
+
+			_, err = orgClient.UpdateTeam(c.Request().Context(), team)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(team)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		}).Delete(func(c flamego.Context, orgClient organizationClient, team *grafana.Team) string {
+			status, err := orgClient.DeleteTeam(c.Request().Context(), team)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+
+		teamMembers := func(c flamego.Context, orgClient organizationClient, team *grafana.Team) string {
+			members, err := orgClient.GetTeamMembers(c.Request().Context(), team)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			jsonResponse, err := json.Marshal(members)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}
+
+		f.Combo("/{orgId}/teams/{id}/members", withOrganization, withTeam).Get(teamMembers).Post(func(c flamego.Context, orgClient organizationClient, team *grafana.Team) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			var member grafana.TeamMember
+			err = json.Unmarshal(requestBody, &member)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			status, err := orgClient.AddTeamMember(c.Request().Context(), team, &member)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		}).Put(func(c flamego.Context, orgClient organizationClient, team *grafana.Team) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			members := make([]grafana.TeamMember, 0)
+			err = json.Unmarshal(requestBody, &members)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			_, err = orgClient.SetTeamMembers(c.Request().Context(), team, &members)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			return teamMembers(c, orgClient, team)
+		})
+		f.Combo("/{orgId}/teams/{id}/members/{userId}", withOrganization, withTeam).Patch(func(c flamego.Context, orgClient organizationClient, team *grafana.Team) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			var member grafana.TeamMember
+			err = json.Unmarshal(requestBody, &member)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+			member.UserId, _ = strconv.ParseInt(c.Param("userId"), 10, 64)
+
+			status, err := orgClient.UpdateTeamMember(c.Request().Context(), team, &member)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		}).Delete(func(c flamego.Context, orgClient organizationClient, team *grafana.Team) string {
+			member := grafana.TeamMember{}
+			member.UserId, _ = strconv.ParseInt(c.Param("userId"), 10, 64)
+
+			status, err := orgClient.RemoveTeamMember(c.Request().Context(), team, &member)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+
+		/*
+		   - ALERT RULES FOR ORGANIZATION -
+		   Retrieving all alert rules | alert rules of folder | alert rules of group:
+		   GET
+		   .../organizations/{orgId}/alert-rules/ (.../organizations/11/alert-rules/ || .../organizations/11/alert-rules/?folder=nErXDvCkzz || .../organizations/11/alert-rules/?folder=Alerts&group=cpu)
+
+		   Creating alert rule:
+		   POST
+		   .../organizations/{orgId}/alert-rules/ (data: {"folderUID": "Alerts", "ruleGroup": "cpu", "title": "High CPU", "condition": "C", "data": [...]})
+
+		   Retrieving | Updating | Deleting single alert rule:
+		   GET | PUT | DELETE
+		   .../organizations/{orgId}/alert-rules/{uid} (.../organizations/11/alert-rules/bdx5xqsn1kxdsf)
+
+		   Retrieving | Replacing | Deleting rule group of folder:
+		   GET | PUT | DELETE
+		   .../organizations/{orgId}/alert-rules/{folder}/{group} (.../organizations/11/alert-rules/Alerts/cpu) (data: {"interval": 60, "rules": [...]})
+
+		   The folder of the rules is given by id, uid or title, as for the .../folders/{id} route.
+		*/
+
+		f.Combo("/{orgId}/alert-rules/", withOrganization).Get(func(c flamego.Context, orgClient organizationClient) string {
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
+
+			folderUid := ""
+			if c.QueryTrim("folder") != "" {
+				folder, err := getFolder(c.Request().Context(), orgClient, c.QueryTrim("folder"))
+				if err != nil {
+					log.Print("Got error: " + err.Error())
+					c.ResponseWriter().WriteHeader(errorStatus(err))
+					return ""
+				}
+				folderUid = folder.Uid
+			}
+
+			all, err := orgClient.GetAlertRules(c.Request().Context())
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			rules := make([]grafana.AlertRule, 0, len(all))
+			for _, rule := range all {
+				if folderUid != "" && rule.FolderUid != folderUid {
+					continue
+				}
+				if c.QueryTrim("group") != "" && rule.RuleGroup != c.QueryTrim("group") {
+					continue
+				}
+				rules = append(rules, rule)
+			}
+
+			// Grafana lists all alert rules at once
+			total := len(rules)
+			if paged {
+				start, end := pageBounds(len(rules), page)
+				rules = rules[start:end]
+				writePageHeaders(c, page, len(rules), int64(total))
+			} else {
+				c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(total))
+			}
+
+			jsonResponse, err := json.Marshal(rules)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Post(func(c flamego.Context, orgClient organizationClient) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			var rule grafana.AlertRule
+			err = json.Unmarshal(requestBody, &rule)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			if rule.FolderUid == "" || rule.RuleGroup == "" {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			folder, err := getFolder(c.Request().Context(), orgClient, rule.FolderUid)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+			rule.FolderUid = folder.Uid
+
+			_, err = orgClient.CreateAlertRule(c.Request().Context(), &rule)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(rule)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		})
+
+		// withAlertRule retrieves the {uid} alert rule of the organization
+		withAlertRule := func(c flamego.Context, orgClient organizationClient) {
+			rule := &grafana.AlertRule{Uid: c.Param("uid")}
+			c.Map(rule)
+
+			_, err := orgClient.GetAlertRule(c.Request().Context(), rule)
+			if errors.Is(err, grafana.ErrNotFound) {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+		}
+
+		f.Combo("/{orgId}/alert-rules/{uid}", withOrganization, withAlertRule).Get(func(c flamego.Context, rule *grafana.AlertRule) string {
+			jsonResponse, err := json.Marshal(rule)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Put(func(c flamego.Context, orgClient organizationClient, rule *grafana.AlertRule) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			uid, folderUid := rule.Uid, rule.FolderUid
+			err = json.Unmarshal(requestBody, rule)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+			rule.Uid = uid
+
+			if rule.FolderUid == "" {
+				rule.FolderUid = folderUid
+			} else if rule.FolderUid != folderUid {
+				folder, err := getFolder(c.Request().Context(), orgClient, rule.FolderUid)
+				if err != nil {
+					log.Print("Got error: " + err.Error())
+					c.ResponseWriter().WriteHeader(errorStatus(err))
+					return "false"
+				}
+				rule.FolderUid = folder.Uid
+			}
+
+			_, err = orgClient.UpdateAlertRule(c.Request().Context(), rule)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(rule)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		}).Delete(func(c flamego.Context, orgClient organizationClient, rule *grafana.AlertRule) string {
+			status, err := orgClient.DeleteAlertRule(c.Request().Context(), rule)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+
+		// withAlertRuleGroup resolves the {folder} of the {group} rule group by id, uid or title
+		withAlertRuleGroup := func(c flamego.Context, orgClient organizationClient) {
+			group := &grafana.AlertRuleGroup{Title: c.Param("group")}
+			c.Map(group)
+
+			folder, err := getFolder(c.Request().Context(), orgClient, c.Param("folder"))
+			group.FolderUid = folder.Uid
+			if errors.Is(err, grafana.ErrNotFound) {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+		}
+
+		f.Combo("/{orgId}/alert-rules/{folder}/{group}", withOrganization, withAlertRuleGroup).Get(func(c flamego.Context, orgClient organizationClient, group *grafana.AlertRuleGroup) string {
+			_, err := orgClient.GetAlertRuleGroup(c.Request().Context(), group)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			jsonResponse, err := json.Marshal(group)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 				return ""
 			}
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return string(jsonResponse)
-		}).Delete(func(c flamego.Context) string {
-			if organization.Id == 0 {
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+		}).Put(func(c flamego.Context, orgClient organizationClient, group *grafana.AlertRuleGroup) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			title, folderUid := group.Title, group.FolderUid
+			err = json.Unmarshal(requestBody, group)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
 				return "false"
 			}
+			group.Title, group.FolderUid = title, folderUid
 
-			status, err := grafana.DeleteOrganization(&organization)
+			_, err = orgClient.SetAlertRuleGroup(c.Request().Context(), group)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(group)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
 			}
-			fmt.Printf("Results: %v\n", status)
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		}).Delete(func(c flamego.Context, orgClient organizationClient, group *grafana.AlertRuleGroup) string {
+			status, err := orgClient.DeleteAlertRuleGroup(c.Request().Context(), group)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return strconv.FormatBool(status)
 		})
 
-		var orgServiceUser grafana.User
 		/*
-		   - DASHBOARDS FOR ORGANIZATION -
-
-		   Retieving:
+		   - CONTACT POINTS FOR ORGANIZATION -
+		   Retrieving all contact points | contact points of name:
 		   GET
-		   .../organizations/{orgId}/dashboards/ (.../organizations/11/dashboards/ || .../organizations/test/dashboards/)
-
-		   Retieving | Deleting single dashboard:
-		   GET | DELETE
-		   .../organizations/{orgId}/dashboards/{uid} (.../organizations/11/dashboards/GPXicXZRk || .../organizations/test/dashboards/organization%20title || .../organizations/test/dashboards/23)
+		   .../organizations/{orgId}/contact-points/ (.../organizations/11/contact-points/ || .../organizations/11/contact-points/?name=ops)
 
-		   Creating dashboard:
+		   Creating contact point:
 		   POST
-		   .../organizations/{orgId}/dashboards/ (data: {})
-		*/
-
-		f.Combo("/{orgId}/dashboards/", func(c flamego.Context) {
-			var err error
-			organization, err = getOrganization(c.Param("orgId"))
-			if err != nil && err.Error() == "Empty result" {
-				organization = grafana.Organization{}
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
-			} else if err != nil {
-				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-			} else {
-				orgServiceUser.Login = "svc" + strconv.FormatInt(organization.Id, 10) + "." + fmt.Sprintf("%x", md5.Sum([]byte(organization.Name)))
-				orgServiceUser.Password = util.RandString(12)
-				orgServiceUser.OrgId = organization.Id
+		   .../organizations/{orgId}/contact-points/ (data: {"name": "ops", "type": "email", "settings": {"addresses": "ops@localhost"}})
 
-				_, err = grafana.CreateUser(&orgServiceUser)
-				if err != nil && strings.Contains(err.Error(), "already exists") {
-					_, err = grafana.GetUser(&orgServiceUser)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
-					_, err = grafana.UpdateUserPassword(&orgServiceUser)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
-				} else if err != nil {
-					log.Print("Got error: " + err.Error())
-				}
+		   Retrieving | Updating | Deleting single contact point:
+		   GET | PUT | DELETE
+		   .../organizations/{orgId}/contact-points/{id} (.../organizations/11/contact-points/dde8e0a4 || .../organizations/11/contact-points/ops)
 
-				if orgServiceUser.Id > 0 {
-					serviceUserOrgs := []grafana.UserOrganization{}
-					serviceUserOrgs = append(serviceUserOrgs, grafana.UserOrganization{
-						Id:   organization.Id,
-						Name: organization.Name,
-						Role: "Admin",
-					})
+		   Retrieving | Replacing notification policy tree:
+		   GET | PUT
+		   .../organizations/{orgId}/notification-policies (data: {"receiver": "ops", "group_by": ["alertname"], "routes": [{"receiver": "dba", "object_matchers": [["team", "=", "dba"]]}]})
+		*/
 
-					_, err = grafana.SetUserOrganizations(&orgServiceUser, &serviceUserOrgs)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
-				}
+		f.Combo("/{orgId}/contact-points/", withOrganization).Get(func(c flamego.Context, orgClient organizationClient) string {
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
 			}
-		}).Get(func(c flamego.Context) string {
-			if organization.Id == 0 {
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+
+			all, err := orgClient.GetContactPoints(c.Request().Context(), c.QueryTrim("name"))
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 				return ""
 			}
 
-			dashboards, err := grafana.GetDashboardsForUser(&orgServiceUser)
+			// Grafana lists all contact points at once
+			contactPoints := all
+			if paged {
+				start, end := pageBounds(len(contactPoints), page)
+				contactPoints = contactPoints[start:end]
+				writePageHeaders(c, page, len(contactPoints), int64(len(all)))
+			} else {
+				c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(contactPoints)))
+			}
 
-			jsonResponse, err := json.Marshal(dashboards)
+			jsonResponse, err := json.Marshal(contactPoints)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 				return ""
 			}
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return string(jsonResponse)
-		}).Post(func(c flamego.Context) string {
+		}).Post(func(c flamego.Context, orgClient organizationClient) string {
 			requestBody, err := c.Request().Body().Bytes()
 			if err != nil {
 				log.Print("Got error: " + err.Error())
 			}
-			var dashboard grafana.Dashboard
-			_, err = grafana.GetDashboardForUser(&orgServiceUser, &dashboard)
-			if err != nil && err.Error() == "Empty result" {
-				dashboard = grafana.Dashboard{}
-			}
-			err = json.Unmarshal(requestBody, &dashboard)
+
+			var contactPoint grafana.ContactPoint
+			err = json.Unmarshal(requestBody, &contactPoint)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-			}
-			dashboard.Overwrite = true
-			if len(dashboard.Message) == 0 {
-				dashboard.Message = "Grafana adapter update " + time.Now().Format("02-01-2006 15:04:05")
-			}
-			_, err = grafana.UpdateDashboardForUser(&orgServiceUser, &dashboard)
-			if err != nil && strings.Contains(err.Error(), "Dashboard not found") {
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
-				return "false"
-			} else if err != nil && strings.Contains(err.Error(), "Dashboard name cannot be the same") {
 				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
 				return "false"
-			} else if err != nil {
+			}
+
+			_, err = orgClient.CreateContactPoint(c.Request().Context(), &contactPoint)
+			if err != nil {
 				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
 			}
 
-			result, err := json.Marshal(dashboard)
+			result, err := json.Marshal(contactPoint)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
 			}
 
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return string(result)
 		})
-		var dashboard grafana.Dashboard
-		f.Combo("/{orgId}/dashboards/{uid}", func(c flamego.Context) {
-			var err error
-			organization, err = getOrganization(c.Param("orgId"))
-			if err != nil && err.Error() == "Empty result" {
-				organization = grafana.Organization{}
+
+		// withContactPoint resolves the {id} contact point of the organization by uid or name
+		withContactPoint := func(c flamego.Context, orgClient organizationClient) {
+			contactPoint := &grafana.ContactPoint{Uid: c.Param("id")}
+			c.Map(contactPoint)
+
+			_, err := orgClient.GetContactPoint(c.Request().Context(), contactPoint)
+			if errors.Is(err, grafana.ErrNotFound) {
+				*contactPoint = grafana.ContactPoint{Name: c.Param("id")}
+				_, err = orgClient.GetContactPoint(c.Request().Context(), contactPoint)
+			}
+			if errors.Is(err, grafana.ErrNotFound) {
 				c.ResponseWriter().WriteHeader(http.StatusNotFound)
 			} else if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-			} else {
-				orgServiceUser.Login = "svc" + strconv.FormatInt(organization.Id, 10) + "." + fmt.Sprintf("%x", md5.Sum([]byte(organization.Name)))
-				orgServiceUser.Password = util.RandString(12)
-				orgServiceUser.OrgId = organization.Id
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+		}
 
-				_, err = grafana.CreateUser(&orgServiceUser)
-				if err != nil && strings.Contains(err.Error(), "already exists") {
-					_, err = grafana.GetUser(&orgServiceUser)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
-					_, err = grafana.UpdateUserPassword(&orgServiceUser)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
-				} else if err != nil {
-					log.Print("Got error: " + err.Error())
-				}
+		f.Combo("/{orgId}/contact-points/{id}", withOrganization, withContactPoint).Get(func(c flamego.Context, contactPoint *grafana.ContactPoint) string {
+			jsonResponse, err := json.Marshal(contactPoint)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Put(func(c flamego.Context, orgClient organizationClient, contactPoint *grafana.ContactPoint) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
 
-				if orgServiceUser.Id > 0 {
-					serviceUserOrgs := []grafana.UserOrganization{}
-					serviceUserOrgs = append(serviceUserOrgs, grafana.UserOrganization{
-						Id:   organization.Id,
-						Name: organization.Name,
-						Role: "Admin",
-					})
+			uid := contactPoint.Uid
+			err = json.Unmarshal(requestBody, contactPoint)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+			contactPoint.Uid = uid
 
-					_, err = grafana.SetUserOrganizations(&orgServiceUser, &serviceUserOrgs)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
+			_, err = orgClient.UpdateContactPoint(c.Request().Context(), contactPoint)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
 
-					dashboard = grafana.Dashboard{}
-					uid := c.Param("uid")
-					id, _ := strconv.ParseInt(uid, 10, 64)
-					if id > 0 {
-						dashboard.Dashboard.Id = id
-						_, err := grafana.GetDashboardForUser(&orgServiceUser, &dashboard)
-						if err != nil {
-							log.Print("Got error: " + err.Error())
-						}
-					}
-					if len(uid) > 0 && dashboard.Dashboard.Title == "" {
-						reName := regexp.MustCompile(`^([\p{L}\d\s_!-\.@|\]\[\(\)]+)*$`)
-						if reName.MatchString(uid) {
-							dashboard.Dashboard.Title = uid
-							_, err := grafana.GetDashboardForUser(&orgServiceUser, &dashboard)
-							if err != nil {
-								log.Print("Got error: " + err.Error())
-							}
-						}
-					}
-				}
+			result, err := json.Marshal(contactPoint)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
 			}
-		}).Get(func(c flamego.Context) string {
-			if dashboard.Dashboard.Uid == "" {
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		}).Delete(func(c flamego.Context, orgClient organizationClient, contactPoint *grafana.ContactPoint) string {
+			status, err := orgClient.DeleteContactPoint(c.Request().Context(), contactPoint)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+
+		f.Combo("/{orgId}/notification-policies", withOrganization).Get(func(c flamego.Context, orgClient organizationClient) string {
+			policy, err := orgClient.GetNotificationPolicyTree(c.Request().Context())
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 				return ""
 			}
-			jsonResponse, err := json.Marshal(&dashboard)
+
+			jsonResponse, err := json.Marshal(policy)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 				return ""
 			}
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return string(jsonResponse)
-		}).Delete(func(c flamego.Context) string {
-			if dashboard.Dashboard.Uid == "" {
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+		}).Put(func(c flamego.Context, orgClient organizationClient) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			var policy grafana.NotificationPolicy
+			err = json.Unmarshal(requestBody, &policy)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
 				return "false"
 			}
 
-			status, err := grafana.DeleteDashboardForUser(&orgServiceUser, &dashboard)
+			status, err := orgClient.SetNotificationPolicyTree(c.Request().Context(), &policy)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 			}
 
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
@@ -614,202 +2847,272 @@ func Start() {
 		})
 
 		/*
-		   - FOLDERS FOR ORGANIZATION -
-		   Retieving all folders:
+		   - SILENCES FOR ORGANIZATION -
+		   Retrieving active | all silences:
 		   GET
-		   .../organizations/{orgId}/folders (.../organizations/11/dashboards/)
+		   .../organizations/{orgId}/silences/ (.../organizations/11/silences/ || .../organizations/11/silences/?state=all)
 
-		   Retieving | Updating single fodler:
+		   Creating silence (from now unless startsAt is given, for duration or until endsAt):
+		   POST
+		   .../organizations/{orgId}/silences/ (data: {"matchers": [{"name": "team", "value": "dba", "isEqual": true}], "duration": "2h", "comment": "maintenance"})
+
+		   Retrieving | Expiring single silence:
 		   GET | DELETE
-		   .../organizations/{orgId}/folders/{id} (.../organizations/11/folders/nErXDvCkzz | .../organizations/11/folders/11)
+		   .../organizations/{orgId}/silences/{id} (.../organizations/11/silences/0e4d9d59-9d43-4a0c-9e8d-6b3b4f1d4e2a)
 
-		   Creating folder:
+		   Retrieving all mute timings:
+		   GET
+		   .../organizations/{orgId}/mute-timings/
+
+		   Creating mute timing:
 		   POST
-		   .../organizations/{orgId}/folders/ (data: {})
+		   .../organizations/{orgId}/mute-timings/ (data: {"name": "weekends", "time_intervals": [{"weekdays": ["saturday", "sunday"]}]})
+
+		   Retrieving | Updating | Deleting single mute timing:
+		   GET | PUT | DELETE
+		   .../organizations/{orgId}/mute-timings/{name} (.../organizations/11/mute-timings/weekends)
 		*/
 
-		var folder grafana.Folder
-		f.Combo("/{orgId}/folders/", func(c flamego.Context) {
-			var err error
-			organization, err = getOrganization(c.Param("orgId"))
-			if err != nil && err.Error() == "Empty result" {
-				organization = grafana.Organization{}
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
-			} else if err != nil {
+		f.Combo("/{orgId}/silences/", withOrganization).Get(func(c flamego.Context, orgClient organizationClient) string {
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
+
+			state := c.QueryTrim("state")
+			if state == "" {
+				state = grafana.SilenceActive
+			}
+
+			all, err := orgClient.GetSilences(c.Request().Context())
+			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-			} else {
-				orgServiceUser.Login = "svc" + strconv.FormatInt(organization.Id, 10) + "." + fmt.Sprintf("%x", md5.Sum([]byte(organization.Name)))
-				orgServiceUser.Password = util.RandString(12)
-				orgServiceUser.OrgId = organization.Id
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
 
-				_, err = grafana.CreateUser(&orgServiceUser)
-				if err != nil && strings.Contains(err.Error(), "already exists") {
-					_, err = grafana.GetUser(&orgServiceUser)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
-					_, err = grafana.UpdateUserPassword(&orgServiceUser)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
-				} else if err != nil {
-					log.Print("Got error: " + err.Error())
+			silences := make([]grafana.Silence, 0, len(all))
+			for _, silence := range all {
+				if state == "all" || silence.State() == state {
+					silences = append(silences, silence)
 				}
+			}
+
+			// Grafana lists all silences at once
+			total := len(silences)
+			if paged {
+				start, end := pageBounds(len(silences), page)
+				silences = silences[start:end]
+				writePageHeaders(c, page, len(silences), int64(total))
+			} else {
+				c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(total))
+			}
 
-				if orgServiceUser.Id > 0 {
-					serviceUserOrgs := []grafana.UserOrganization{}
-					serviceUserOrgs = append(serviceUserOrgs, grafana.UserOrganization{
-						Id:   organization.Id,
-						Name: organization.Name,
-						Role: "Admin",
-					})
+			jsonResponse, err := json.Marshal(silences)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Post(func(c flamego.Context, orgClient organizationClient) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
 
-					_, err = grafana.SetUserOrganizations(&orgServiceUser, &serviceUserOrgs)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
+			var data struct {
+				grafana.Silence
+				Duration string `json:"duration"`
+			}
+			err = json.Unmarshal(requestBody, &data)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
+			}
+
+			silence := data.Silence
+			if silence.StartsAt.IsZero() {
+				silence.StartsAt = time.Now().UTC()
+			}
+			if data.Duration != "" {
+				duration, err := time.ParseDuration(data.Duration)
+				if err != nil || duration <= 0 {
+					c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+					return "false"
 				}
+				silence.EndsAt = silence.StartsAt.Add(duration)
 			}
-		}).Get(func(c flamego.Context) string {
-			if organization.Id == 0 {
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+			// silences are always told apart as created through the adapter
+			silence.Id = ""
+			silence.CreatedBy = identity(c)
+
+			_, err = orgClient.CreateSilence(c.Request().Context(), &silence)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(silence)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		})
+		f.Combo("/{orgId}/silences/{id}", withOrganization).Get(func(c flamego.Context, orgClient organizationClient) string {
+			silence := grafana.Silence{Id: c.Param("id")}
+			_, err := orgClient.GetSilence(c.Request().Context(), &silence)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+
+			jsonResponse, err := json.Marshal(silence)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return ""
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		}).Delete(func(c flamego.Context, orgClient organizationClient) string {
+			silence := grafana.Silence{Id: c.Param("id")}
+			status, err := orgClient.ExpireSilence(c.Request().Context(), &silence)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+
+		f.Combo("/{orgId}/mute-timings/", withOrganization).Get(func(c flamego.Context, orgClient organizationClient) string {
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
+
+			all, err := orgClient.GetMuteTimings(c.Request().Context())
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 				return ""
 			}
-			folders, err := grafana.GetFoldersForUser(&orgServiceUser)
 
-			jsonResponse, err := json.Marshal(folders)
+			// Grafana lists all mute timings at once
+			muteTimings := all
+			if paged {
+				start, end := pageBounds(len(muteTimings), page)
+				muteTimings = muteTimings[start:end]
+				writePageHeaders(c, page, len(muteTimings), int64(len(all)))
+			} else {
+				c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(muteTimings)))
+			}
+
+			jsonResponse, err := json.Marshal(muteTimings)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 				return ""
 			}
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return string(jsonResponse)
-
-		}).Post(func(c flamego.Context) string {
+		}).Post(func(c flamego.Context, orgClient organizationClient) string {
 			requestBody, err := c.Request().Body().Bytes()
 			if err != nil {
 				log.Print("Got error: " + err.Error())
 			}
 
-			var folder grafana.Folder
-
-			err = json.Unmarshal(requestBody, &folder)
+			var muteTiming grafana.MuteTiming
+			err = json.Unmarshal(requestBody, &muteTiming)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-			}
-
-			_, err = grafana.CreateFolderForUser(&orgServiceUser, &folder)
-			if err != nil && strings.Contains(err.Error(), "Folder name cannot be the same") {
 				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
 				return "false"
-			} else if err != nil && strings.Contains(err.Error(), "already exists") {
-				c.ResponseWriter().WriteHeader(http.StatusConflict)
-				return "false"
-			} else if err != nil {
+			}
+
+			_, err = orgClient.CreateMuteTiming(c.Request().Context(), &muteTiming)
+			if err != nil {
 				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
 			}
 
-			result, err := json.Marshal(folder)
+			result, err := json.Marshal(muteTiming)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
 			}
 
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return string(result)
 		})
-		f.Combo("/{orgId}/folders/{id}", func(c flamego.Context) {
-			var err error
-			organization, err = getOrganization(c.Param("orgId"))
-			if err != nil && err.Error() == "Empty result" {
-				organization = grafana.Organization{}
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
-			} else if err != nil {
-				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-			} else {
-				orgServiceUser.Login = "svc" + strconv.FormatInt(organization.Id, 10) + "." + fmt.Sprintf("%x", md5.Sum([]byte(organization.Name)))
-				orgServiceUser.Password = util.RandString(12)
-				orgServiceUser.OrgId = organization.Id
-
-				_, err = grafana.CreateUser(&orgServiceUser)
-				if err != nil && strings.Contains(err.Error(), "already exists") {
-					_, err = grafana.GetUser(&orgServiceUser)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
-					_, err = grafana.UpdateUserPassword(&orgServiceUser)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
-				} else if err != nil {
-					log.Print("Got error: " + err.Error())
-				}
-
-				if orgServiceUser.Id > 0 {
-					serviceUserOrgs := []grafana.UserOrganization{}
-					serviceUserOrgs = append(serviceUserOrgs, grafana.UserOrganization{
-						Id:   organization.Id,
-						Name: organization.Name,
-						Role: "Admin",
-					})
-
-					_, err = grafana.SetUserOrganizations(&orgServiceUser, &serviceUserOrgs)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
 
-					folder = grafana.Folder{}
-					id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
-					uid := c.Param("id")
-					if id > 0 {
-						folder.Id = id
-						_, err := grafana.GetFolderByIdForUser(&orgServiceUser, &folder)
-						if err != nil && err.Error() == "Empty result" {
-							folder = grafana.Folder{}
-							c.ResponseWriter().WriteHeader(http.StatusNotFound)
-						} else if err != nil {
-							log.Print("Got error: " + err.Error())
-							c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-						}
-					}
+		// withMuteTiming retrieves the {name} mute timing of the organization
+		withMuteTiming := func(c flamego.Context, orgClient organizationClient) {
+			muteTiming := &grafana.MuteTiming{Name: c.Param("name")}
+			c.Map(muteTiming)
 
-					if len(uid) > 0 && folder.Uid == "" {
-						folder.Uid = uid
-						_, err := grafana.GetFolderForUser(&orgServiceUser, &folder)
-						if err != nil && err.Error() == "Empty result" {
-							folder = grafana.Folder{}
-							c.ResponseWriter().WriteHeader(http.StatusNotFound)
-						} else if err != nil {
-							log.Print("Got error: " + err.Error())
-							c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-						}
-					}
-				}
-			}
-		}).Get(func(c flamego.Context) string {
-			if folder.Uid == "" {
+			_, err := orgClient.GetMuteTiming(c.Request().Context(), muteTiming)
+			if errors.Is(err, grafana.ErrNotFound) {
 				c.ResponseWriter().WriteHeader(http.StatusNotFound)
-				return ""
+			} else if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 			}
-			jsonResponse, err := json.Marshal(folder)
+		}
+
+		f.Combo("/{orgId}/mute-timings/{name}", withOrganization, withMuteTiming).Get(func(c flamego.Context, muteTiming *grafana.MuteTiming) string {
+			jsonResponse, err := json.Marshal(muteTiming)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 				return ""
 			}
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return string(jsonResponse)
-		}).Delete(func(c flamego.Context) string {
-			if folder.Uid == "" {
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+		}).Put(func(c flamego.Context, orgClient organizationClient, muteTiming *grafana.MuteTiming) string {
+			requestBody, err := c.Request().Body().Bytes()
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			name := muteTiming.Name
+			err = json.Unmarshal(requestBody, muteTiming)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
 				return "false"
 			}
+			muteTiming.Name = name
 
-			status, err := grafana.DeleteFolderForUser(&orgServiceUser, &folder)
+			_, err = orgClient.UpdateMuteTiming(c.Request().Context(), muteTiming)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			result, err := json.Marshal(muteTiming)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+			}
+
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(result)
+		}).Delete(func(c flamego.Context, orgClient organizationClient, muteTiming *grafana.MuteTiming) string {
+			status, err := orgClient.DeleteMuteTiming(c.Request().Context(), muteTiming)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 			}
 
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
@@ -817,219 +3120,354 @@ func Start() {
 		})
 
 		/*
-		   - DATASOURCES FOR ORGANIZATION -
-		   Retieving all datasources:
+		   - ANNOTATIONS FOR ORGANIZATION -
+		   Retrieving annotations (latest first, 100 unless limit is given) between from and to (epoch milliseconds), of all | any tags, of dashboard:
 		   GET
-		   .../organizations/{orgId}/datasources (.../organizations/11/datasources/)
-
-		   Retieving | Updating single datasource:
-		   GET | DELETE
-		   .../organizations/{orgId}/datasources/{id} (.../organizations/11/datasources/nErXDvCkzz | .../organizations/11/datasources/11)
+		   .../organizations/{orgId}/annotations/ (.../organizations/11/annotations/?from=1700000000000&to=1700003600000&tags=deploy&tags=api || ...?tags=deploy,api&matchAny=true || ...?dashboardUid=GPXicXZRk&limit=500)
 
-		   Creating datasource:
+		   Creating annotation (Grafana sets time to now when it is left out):
 		   POST
-		   .../organizations/{orgId}/datasources/ (data: {})
+		   .../organizations/{orgId}/annotations/ (data: {"text": "Deployed api v1.2.3", "tags": ["deploy", "api"], "dashboardUID": "GPXicXZRk"})
+
+		   Deleting single annotation:
+		   DELETE
+		   .../organizations/{orgId}/annotations/{id} (.../organizations/11/annotations/17)
 		*/
 
-		var datasource grafana.Datasource
-		f.Combo("/{orgId}/datasources/", func(c flamego.Context) {
-			var err error
-			organization, err = getOrganization(c.Param("orgId"))
-			if err != nil && err.Error() == "Empty result" {
-				organization = grafana.Organization{}
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
-			} else if err != nil {
-				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-			} else {
-				orgServiceUser.Login = "svc" + strconv.FormatInt(organization.Id, 10) + "." + fmt.Sprintf("%x", md5.Sum([]byte(organization.Name)))
-				orgServiceUser.Password = util.RandString(12)
-				orgServiceUser.OrgId = organization.Id
+		f.Combo("/{orgId}/annotations/", withOrganization).Get(func(c flamego.Context, orgClient organizationClient) string {
+			page, paged, err := pageQuery(c)
+			if err != nil {
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return err.Error()
+			}
 
-				_, err = grafana.CreateUser(&orgServiceUser)
-				if err != nil && strings.Contains(err.Error(), "already exists") {
-					_, err = grafana.GetUser(&orgServiceUser)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
-					_, err = grafana.UpdateUserPassword(&orgServiceUser)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
+			query := grafana.AnnotationQuery{
+				DashboardUid: c.QueryTrim("dashboardUid"),
+				MatchAny:     c.QueryBool("matchAny"),
+			}
+			for _, tags := range c.Request().URL.Query()["tags"] {
+				for _, tag := range strings.Split(tags, ",") {
+					if strings.TrimSpace(tag) != "" {
+						query.Tags = append(query.Tags, strings.TrimSpace(tag))
 					}
-				} else if err != nil {
-					log.Print("Got error: " + err.Error())
 				}
-
-				if orgServiceUser.Id > 0 {
-					serviceUserOrgs := []grafana.UserOrganization{}
-					serviceUserOrgs = append(serviceUserOrgs, grafana.UserOrganization{
-						Id:   organization.Id,
-						Name: organization.Name,
-						Role: "Admin",
-					})
-
-					_, err = grafana.SetUserOrganizations(&orgServiceUser, &serviceUserOrgs)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
+			}
+			for param, value := range map[string]*int64{"from": &query.From, "to": &query.To} {
+				if c.QueryTrim(param) != "" {
+					*value, err = strconv.ParseInt(c.QueryTrim(param), 10, 64)
+					if err != nil || *value < 0 {
+						c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+						return "The " + param + " query parameter must be epoch milliseconds"
 					}
 				}
 			}
-		}).Get(func(c flamego.Context) string {
-			if organization.Id == 0 {
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+			if c.QueryTrim("limit") != "" {
+				query.Limit, err = strconv.Atoi(c.QueryTrim("limit"))
+				if err != nil || query.Limit < 1 {
+					c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+					return "The limit query parameter must be a positive integer"
+				}
+			}
+
+			all, err := orgClient.GetAnnotations(c.Request().Context(), query)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 				return ""
 			}
-			datasources, err := grafana.GetDatasourcesForUser(&orgServiceUser)
 
-			jsonResponse, err := json.Marshal(datasources)
+			// Grafana lists the annotations up to the limit at once
+			annotations := all
+			if paged {
+				start, end := pageBounds(len(annotations), page)
+				annotations = annotations[start:end]
+				writePageHeaders(c, page, len(annotations), int64(len(all)))
+			} else {
+				c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(annotations)))
+			}
+
+			jsonResponse, err := json.Marshal(annotations)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 				return ""
 			}
 			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return string(jsonResponse)
-
-		}).Post(func(c flamego.Context) string {
+		}).Post(func(c flamego.Context, orgClient organizationClient) string {
 			requestBody, err := c.Request().Body().Bytes()
 			if err != nil {
 				log.Print("Got error: " + err.Error())
 			}
 
-			var datasource grafana.Datasource
-
-			err = json.Unmarshal(requestBody, &datasource)
+			var annotation grafana.Annotation
+			err = json.Unmarshal(requestBody, &annotation)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+				return "false"
 			}
 
-			_, err = grafana.CreateDatasourceForUser(&orgServiceUser, &datasource)
-			if err != nil && strings.Contains(err.Error(), "Required") {
-				c.ResponseWriter().WriteHeader(http.StatusUnprocessableEntity)
-				return "false"
-			} else if err != nil && strings.Contains(err.Error(), "already exists") {
-				c.ResponseWriter().WriteHeader(http.StatusConflict)
-				return "false"
-			} else if err != nil {
+			_, err = orgClient.CreateAnnotation(c.Request().Context(), &annotation)
+			if err != nil {
 				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
 			}
 
-			result, err := json.Marshal(datasource)
+			result, err := json.Marshal(annotation)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
 			}
 
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
 			return string(result)
 		})
-		f.Combo("/{orgId}/datasources/{id}", func(c flamego.Context) {
-			var err error
-			organization, err = getOrganization(c.Param("orgId"))
-			if err != nil && err.Error() == "Empty result" {
-				organization = grafana.Organization{}
+		f.Delete("/{orgId}/annotations/{id}", withOrganization, func(c flamego.Context, orgClient organizationClient) string {
+			annotation := grafana.Annotation{}
+			annotation.Id, _ = strconv.ParseInt(c.Param("id"), 10, 64)
+			if annotation.Id == 0 {
 				c.ResponseWriter().WriteHeader(http.StatusNotFound)
-			} else if err != nil {
+				return "false"
+			}
+
+			status, err := orgClient.DeleteAnnotation(c.Request().Context(), &annotation)
+			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-			} else {
-				orgServiceUser.Login = "svc" + strconv.FormatInt(organization.Id, 10) + "." + fmt.Sprintf("%x", md5.Sum([]byte(organization.Name)))
-				orgServiceUser.Password = util.RandString(12)
-				orgServiceUser.OrgId = organization.Id
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+			}
 
-				_, err = grafana.CreateUser(&orgServiceUser)
-				if err != nil && strings.Contains(err.Error(), "already exists") {
-					_, err = grafana.GetUser(&orgServiceUser)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
-					_, err = grafana.UpdateUserPassword(&orgServiceUser)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
-				} else if err != nil {
-					log.Print("Got error: " + err.Error())
-				}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return strconv.FormatBool(status)
+		})
+	})
+
+	/*
+	   - ANNOTATIONS FOR SEVERAL ORGANIZATIONS -
+	   Creating the same annotation in every listed | every organization, the result of each organization is reported apart
+	   (200 when all of them succeeded, 207 otherwise). Whatever ORG_ACCESS, it is created as the admin user with
+	   X-Grafana-Org-Id, the organizations it is not a member of are left out of every organization and fail when listed:
+	   POST
+	   .../annotations (data: {"organizations": [2, 3], "annotation": {"text": "Deployed api v1.2.3", "tags": ["deploy"]}} || {"all": true, "annotation": {...}})
+	*/
+	f.Post("/annotations", func(c flamego.Context, client *grafana.Client, access *organizationAccess) string {
+		requestBody, err := c.Request().Body().Bytes()
+		if err != nil {
+			log.Print("Got error: " + err.Error())
+		}
 
-				if orgServiceUser.Id > 0 {
-					serviceUserOrgs := []grafana.UserOrganization{}
-					serviceUserOrgs = append(serviceUserOrgs, grafana.UserOrganization{
-						Id:   organization.Id,
-						Name: organization.Name,
-						Role: "Admin",
-					})
+		var annotationsRequest struct {
+			Organizations []int64            `json:"organizations"`
+			All           bool               `json:"all"`
+			Annotation    grafana.Annotation `json:"annotation"`
+		}
+		err = json.Unmarshal(requestBody, &annotationsRequest)
+		if err != nil || annotationsRequest.All == (len(annotationsRequest.Organizations) > 0) {
+			// an empty list is refused rather than taken for every organization
+			c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+			return "false"
+		}
 
-					_, err = grafana.SetUserOrganizations(&orgServiceUser, &serviceUserOrgs)
-					if err != nil {
-						log.Print("Got error: " + err.Error())
-					}
+		type organizationResult struct {
+			OrgId        int64  `json:"orgId"`
+			Name         string `json:"name,omitempty"`
+			AnnotationId int64  `json:"annotationId,omitempty"`
+			Status       bool   `json:"status"`
+			Error        string `json:"error,omitempty"`
+		}
 
-					datasource = grafana.Datasource{}
-					id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
-					name := c.Param("id")
-					if id > 0 {
-						datasource.Id = id
-						_, err = grafana.GetDatasourceForUser(&orgServiceUser, &datasource)
-					}
+		annotate := func(organization grafana.Organization) (organizationResult, error) {
+			result := organizationResult{OrgId: organization.Id, Name: organization.Name}
 
-					if len(name) > 0 && datasource.Uid == "" {
-						datasource.Name = name
-						_, err = grafana.GetDatasourceForUser(&orgServiceUser, &datasource)
-					}
+			// the admin user does not join the organizations for a fan-out, nor is a service user set up
+			orgClient, err := access.memberClient(c.Request().Context(), client, organization)
+			if err == nil {
+				annotation := annotationsRequest.Annotation
+				_, err = orgClient.CreateAnnotation(c.Request().Context(), &annotation)
+				result.AnnotationId = annotation.Id
+			}
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				result.Error = err.Error()
+			}
+			result.Status = err == nil
 
-					if len(name) > 0 && datasource.Uid == "" {
-						datasource.Uid = name
-						_, err = grafana.GetDatasourceForUser(&orgServiceUser, &datasource)
-					}
+			return result, err
+		}
 
-					if err != nil && err.Error() == "Empty result" {
-						datasource = grafana.Datasource{}
-						c.ResponseWriter().WriteHeader(http.StatusNotFound)
-					} else if err != nil {
-						log.Print("Got error: " + err.Error())
-						c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
-					}
+		results := make([]organizationResult, 0)
+		if annotationsRequest.All {
+			organizations, err := reachableOrganizations(c.Request().Context(), client)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(errorStatus(err))
+				return "false"
+			}
+
+			for _, organization := range organizations {
+				result, err := annotate(organization)
+				// the organizations the admin user is not a member of are left out
+				if errors.Is(err, errNotMember) {
+					continue
 				}
+				results = append(results, result)
 			}
-		}).Get(func(c flamego.Context) string {
-			if datasource.Uid == "" {
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
-				return ""
+		} else {
+			for _, id := range annotationsRequest.Organizations {
+				organization, err := getOrganization(c.Request().Context(), client, strconv.FormatInt(id, 10))
+				if err != nil {
+					log.Print("Got error: " + err.Error())
+					results = append(results, organizationResult{OrgId: id, Error: err.Error()})
+					continue
+				}
+				result, _ := annotate(organization)
+				results = append(results, result)
 			}
-			jsonResponse, err := json.Marshal(datasource)
+		}
+
+		status := http.StatusOK
+		for _, result := range results {
+			if !result.Status {
+				status = http.StatusMultiStatus
+			}
+		}
+
+		jsonResponse, err := json.Marshal(results)
+		if err != nil {
+			log.Print("Got error: " + err.Error())
+			c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+			return "false"
+		}
+		c.ResponseWriter().Header().Add("Content-Type", "application/json")
+		c.ResponseWriter().WriteHeader(status)
+		return string(jsonResponse)
+	})
+
+	/*
+	   - DASHBOARDS OF SEVERAL ORGANIZATIONS -
+	   Searching the dashboards of every | every listed organization (by id or name), the organizations failing to
+	   answer are reported apart (200 when all of them answered, 207 otherwise). Whatever ORG_ACCESS, they are searched as
+	   the admin user with X-Grafana-Org-Id, the organizations it is not a member of are left out of every organization and
+	   fail when listed:
+	   GET
+	   .../dashboards/search?query=cpu&tag=prod&starred=true&sort=alpha-desc (.../dashboards/search?query=cpu&organizations=2,ops)
+	*/
+	f.Get("/dashboards/search", func(c flamego.Context, client *grafana.Client, access *organizationAccess) string {
+		search := grafana.DashboardSearch{
+			Query:   c.QueryTrim("query"),
+			Starred: c.QueryBool("starred"),
+			Sort:    c.QueryTrim("sort"),
+		}
+		for _, tags := range c.Request().URL.Query()["tag"] {
+			for _, tag := range strings.Split(tags, ",") {
+				if strings.TrimSpace(tag) != "" {
+					search.Tags = append(search.Tags, strings.TrimSpace(tag))
+				}
+			}
+		}
+
+		type organizationHit struct {
+			OrgId   int64  `json:"orgId"`
+			OrgName string `json:"orgName"`
+			grafana.SearchHit
+		}
+		type organizationFailure struct {
+			OrgId int64  `json:"orgId,omitempty"`
+			Name  string `json:"name,omitempty"`
+			Error string `json:"error"`
+		}
+		var searchResult struct {
+			Hits     []organizationHit     `json:"hits"`
+			Failures []organizationFailure `json:"failures"`
+		}
+		searchResult.Hits = make([]organizationHit, 0)
+		searchResult.Failures = make([]organizationFailure, 0)
+
+		var organizations []grafana.Organization
+		var keys []string
+		for _, values := range c.Request().URL.Query()["organizations"] {
+			for _, key := range strings.Split(values, ",") {
+				if strings.TrimSpace(key) != "" {
+					keys = append(keys, strings.TrimSpace(key))
+				}
+			}
+		}
+		if len(keys) == 0 {
+			var err error
+			organizations, err = reachableOrganizations(c.Request().Context(), client)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				c.ResponseWriter().WriteHeader(errorStatus(err))
 				return ""
 			}
-			c.ResponseWriter().Header().Add("Content-Type", "application/json")
-			return string(jsonResponse)
-		}).Delete(func(c flamego.Context) string {
-			if datasource.Uid == "" {
-				c.ResponseWriter().WriteHeader(http.StatusNotFound)
-				return "false"
-			}
-
-			status, err := grafana.DeleteDatasourceForUser(&orgServiceUser, &datasource)
+		}
+		for _, key := range keys {
+			organization, err := getOrganization(c.Request().Context(), client, key)
 			if err != nil {
 				log.Print("Got error: " + err.Error())
-				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				failure := organizationFailure{Name: key, Error: err.Error()}
+				failure.OrgId, _ = strconv.ParseInt(key, 10, 64)
+				if failure.OrgId > 0 {
+					failure.Name = ""
+				}
+				searchResult.Failures = append(searchResult.Failures, failure)
+				continue
 			}
+			organizations = append(organizations, organization)
+		}
 
-			c.ResponseWriter().Header().Add("Content-Type", "application/json")
-			return strconv.FormatBool(status)
-		})
-	})
+		// at most dashboardSearchConcurrency organizations are searched at once, the hits
+		// are kept in the order of the organizations
+		hits := make([][]grafana.SearchHit, len(organizations))
+		errs := make([]error, len(organizations))
+		slots := make(chan struct{}, dashboardSearchConcurrency)
+		var wg sync.WaitGroup
+		for i, organization := range organizations {
+			wg.Add(1)
+			go func(i int, organization grafana.Organization) {
+				defer wg.Done()
+				slots <- struct{}{}
+				defer func() { <-slots }()
+
+				// the admin user does not join the organizations for a search, nor is a service user set up
+				orgClient, err := access.memberClient(c.Request().Context(), client, organization)
+				if err == nil {
+					hits[i], err = orgClient.SearchDashboards(c.Request().Context(), search)
+				}
+				errs[i] = err
+			}(i, organization)
+		}
+		wg.Wait()
 
-	//index
-	f.Get("/", func(c flamego.Context) string {
-		c.ResponseWriter().Header().Add("Content-Type", "text/html")
-		return `<html><head><title>Grafana Adapter</title></head><body style="display: -webkit-flex; display: flex; -webkit-align-items: center; align-items: center; -webkit-justify-content: center; justify-content: center;"><svg xmlns="http://www.w3.org/2000/svg" viewBox="22.4 66 51 51" width="255" height="255"><g transform="translate(-11.520834,168.12362)" style="fill:none;stroke:#181b1f;stroke-opacity:1"><path style="fill:none;fill-opacity:1;stroke:#181b1f;stroke-width:1.16779;stroke-miterlimit:4;stroke-dasharray:none;stroke-opacity:1" d="m 76.721537,-79.583115 1.20611,-0.206027 2.400742,0.413747 3.709327,2.134183 v 1.270057 l -3.709327,2.134183 -2.400742,0.413747 -1.20611,-0.206028 -0.846379,3.158659 1.147605,0.424625 1.872252,1.558704 2.145246,3.702886 -0.635039,1.09993 -4.279473,-0.0063 -2.285954,-0.84212 -0.941542,-0.781486 -2.312334,2.312334 0.781486,0.941541 0.84212,2.285956 0.0063,4.279473 -1.09993,0.635039 -3.702886,-2.145246 -1.558704,-1.872252 -0.424625,-1.147605 -3.158659,0.846379 0.206028,1.206112 -0.413747,2.40074 -2.134184,3.709327 h -1.270056 l -2.134184,-3.709327 -0.413747,-2.40074 0.206028,-1.206112 -3.158659,-0.846379 -0.424625,1.147605 -1.558704,1.872252 -3.702886,2.145246 -1.09993,-0.635039 0.0063,-4.279473 0.84212,-2.285956 0.781486,-0.941541 -2.312334,-2.312334 -0.941542,0.781486 -2.285954,0.84212 -4.279473,0.0063 -0.635039,-1.09993 2.145246,-3.702886 1.872252,-1.558704 1.147605,-0.424625 -0.846379,-3.158659 -1.20611,0.206028 -2.400742,-0.413747 -3.709327,-2.134183 v -1.270057 l 3.709327,-2.134183 2.400742,-0.413747 1.20611,0.206027 0.846379,-3.158658 -1.147605,-0.424625 -1.872252,-1.558704 -2.145246,-3.702886 0.635039,-1.09993 4.279473,0.0063 2.285954,0.84212 0.941542,0.781487 2.312334,-2.312335 -0.781486,-0.941543 -0.84212,-2.285952 -0.0063,-4.279474 1.09993,-0.635038 3.702886,2.145244 1.558704,1.872252 0.424625,1.147606 3.158659,-0.846379 -0.206028,-1.20611 0.413747,-2.400742 2.134184,-3.709328 h 1.270056 l 2.134184,3.709328 0.413747,2.400742 -0.206028,1.20611 3.158659,0.846379 0.424625,-1.147606 1.558704,-1.872252 3.702886,-2.145244 1.09993,0.635038 -0.0063,4.279474 -0.84212,2.285952 -0.781486,0.941543 2.312334,2.312335 0.941542,-0.781487 2.285954,-0.84212 4.279473,-0.0063 0.635039,1.09993 -2.145246,3.702886 -1.872252,1.558704 -1.147605,0.424625 z"/><path style="fill:none;fill-rule:evenodd;stroke:#181b1f;stroke-width:0.3;stroke-linecap:butt;stroke-linejoin:miter;stroke-miterlimit:4;stroke-dasharray:1.2, 0.6, 0.3, 0.6;stroke-dashoffset:0;stroke-opacity:1" d="m 65.296341,-55.946924 c 0,0 4.006185,-1.028639 6.875738,-3.345164 3.112133,-2.512353 6.03531,-5.231873 7.839866,-11.174642 1.814931,-5.976939 0.245709,-11.529764 0.245709,-11.529764"/> </g> <g transform="translate(90.604454,163.0733)" style="fill:none;stroke:#181b1f;stroke-opacity:1"> <path style="fill:#181b1f;fill-opacity:1;stroke:#181b1f;stroke-width:0.244842;stroke-opacity:1" d="m -25.403747,-74.532795 c 0.995674,0.259703 4.450542,-0.520083 4.133957,0.66916 -1.476606,5.546838 -3.391312,3.457763 -4.859548,6.346846 0.81556,-5.143325 0.374266,-12.942475 -9.500961,-19.296605 -3.31201,-1.292251 -7.804906,-1.710191 -11.444118,-0.551766 -3.815007,1.214384 -7.693639,3.811627 -9.455049,7.406963 -2.926055,5.972575 -4.115684,15.823906 -0.09631,19.952237 4.019377,4.12833 16.783249,6.057468 16.783249,6.057468 l 0.208539,1.190747 -0.418793,2.370163 -2.160214,3.662081 h -1.285549 l -2.160213,-3.662081 -0.418793,-2.370163 0.208539,-1.190747 -3.197184,-0.835598 -0.429803,1.132988 -1.577717,1.848404 -3.74805,2.117921 -1.113346,-0.62695 0.0065,-4.224965 0.852391,-2.256836 0.791018,-0.929549 -2.34054,-2.282883 -0.953025,0.771532 -2.313835,0.831394 -4.331668,0.0063 -0.642783,-1.085921 2.171409,-3.65572 1.895087,-1.538851 1.161603,-0.419215 -0.856702,-3.118427 -1.220821,0.203402 -2.430022,-0.408476 -3.754568,-2.107 v -1.25388 l 3.754568,-2.107 2.430022,-0.408476 1.220821,0.203402 0.856702,-3.118427 -1.161603,-0.419215 -1.895087,-1.538851 -2.171409,-3.65572 0.642783,-1.085921 4.331668,0.0063 2.313835,0.831394 0.953025,0.771532 2.34054,-2.282883 -0.791018,-0.929549 -0.852391,-2.256836 -0.0065,-4.224965 1.113346,-0.62695 3.74805,2.117921 1.577717,1.848404 0.429803,1.132988 3.197184,-0.835598 -0.208539,-1.190747 0.418793,-2.370163 2.160213,-3.662081 h 1.285549 l 2.160214,3.662081 0.418793,2.370163 -0.208539,1.190747 3.197183,0.835598 0.429803,-1.132988 1.577715,-1.848404 3.748046,-2.117921 1.113347,0.62695 -0.0065,4.224965 -0.852392,2.256836 -0.791017,0.929549 2.340539,2.282883 0.953025,-0.771532 2.313833,-0.831394 c 0,0 3.864978,-0.464225 4.363438,0.158551 0,0 -1.774762,4.071397 -2.703826,4.512106 l -1.913259,2.02275 z"/> </g> <path d="m 60.945554,79.828945 c -3.56334,-4.16028 -9.55703,-5.98062 -14.42394,-5.62389 -4.8669,0.35673 -9.07393,3.2743 -11.98739,6.83327 -2.87675,3.51414 -4.60608,8.40632 -4.27654,12.93579 0.2986,4.10429 2.47934,8.276235 5.53084,11.037155 7.32934,6.1694 14.21778,7.67727 18.49458,3.15022 5.39942,-4.52307 6.97817,-13.242135 3.08851,-18.571145 -4.42531,-6.06288 -13.67462,-4.9983 -16.01631,1.81537 -0.64012,1.95159 -0.25617,4.50649 0.87661,6.09897 1.13278,1.59248 3.29474,2.519745 4.55026,2.549135 0.2488,0.002 0.49282,-0.0249 0.72414,-0.05599 l 0.20056,-0.0362 c 0.25564,-0.0525 0.42014,-0.3025 0.36741,-0.55804 -0.0467,-0.22039 -0.24509,-0.3752 -0.47021,-0.36647 l -0.1672,0.003 c -0.19143,-0.004 -0.38386,-0.01 -0.5783,-0.0456 -1.63939,-0.30275 -3.61141,-2.60788 -3.6627,-4.74429 -0.0513,-2.1364 2.19753,-4.81163 4.47931,-5.24314 2.28177,-0.43152 5.25781,0.75646 6.49275,3.17014 1.23494,2.41368 0.48466,6.67475 -1.5365,8.91072 -3.01055,3.33051 -8.494872,3.74582 -11.84404,1.58931 -6.216826,-4.00298 -6.930182,-11.704962 -3.65667,-17.37485 2.992971,-5.183978 10.919238,-8.548925 17.64642,-6.48077 3.26282,1.05432 5.7649,2.51848 7.80348,5.58608 0.62978,0.94767 1.0755,1.93042 1.4304,3.09939 0.27695,0.91224 0.82549,-0.59449 1.01429,-2.23306 0.3006,-2.60887 -1.18244,-1.94087 -4.07976,-5.44511 z" style="fill:#181b1f;fill-opacity:1;stroke-width:0.491871"/></svg></body></html>`
-	})
+		for i, organization := range organizations {
+			// the organizations the admin user is not a member of are left out of a search of all of them
+			if errors.Is(errs[i], errNotMember) && len(keys) == 0 {
+				continue
+			}
+			if errs[i] != nil {
+				log.Print("Got error: " + errs[i].Error())
+				searchResult.Failures = append(searchResult.Failures, organizationFailure{OrgId: organization.Id, Name: organization.Name, Error: errs[i].Error()})
+				continue
+			}
+			for _, hit := range hits[i] {
+				searchResult.Hits = append(searchResult.Hits, organizationHit{OrgId: organization.Id, OrgName: organization.Name, SearchHit: hit})
+			}
+		}
 
-	grafana.NewClient("http://"+settings.GrafanaBackend.Host+":"+strconv.Itoa(settings.GrafanaBackend.Port),
-		settings.GrafanaBackend.Login, settings.GrafanaBackend.Password)
+		status := http.StatusOK
+		if len(searchResult.Failures) > 0 {
+			status = http.StatusMultiStatus
+		}
 
-	if settings.Server.Login != "" && settings.Server.Password != "" {
-		f.Use(auth.Basic(settings.Server.Login, settings.Server.Password))
-	}
-	f.Run(settings.Server.Host, settings.Server.Port)
+		jsonResponse, err := json.Marshal(searchResult)
+		if err != nil {
+			log.Print("Got error: " + err.Error())
+			c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+			return ""
+		}
+		c.ResponseWriter().Header().Set("X-Total-Count", strconv.Itoa(len(searchResult.Hits)))
+		c.ResponseWriter().Header().Add("Content-Type", "application/json")
+		c.ResponseWriter().WriteHeader(status)
+		return string(jsonResponse)
+	})
 }
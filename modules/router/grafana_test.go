@@ -0,0 +1,600 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flamego/flamego"
+
+	grafana "grafana-adapter/modules/external/grafana/apiv1"
+	"grafana-adapter/modules/settings"
+)
+
+const (
+	// stubOrganizations is the number of organizations the requests are spread over.
+	stubOrganizations = 8
+	// stubNotMember is the organization the admin user is not a member of.
+	stubNotMember = 4
+)
+
+var (
+	stubServiceUser = regexp.MustCompile(`^svc(\d+)\.`)
+	stubUser        = regexp.MustCompile(`^user(\d+)(@localhost)?$`)
+	stubEntity      = regexp.MustCompile(`^[dfs](\d+)$`)
+)
+
+// stubOrganization returns the organization the request is scoped to, by the X-Grafana-Org-Id
+// header or by the "svc<id>.<md5>" service user it is authenticated as, 0 for the admin user.
+func stubOrganization(r *http.Request) int64 {
+	if orgId, err := strconv.ParseInt(r.Header.Get("X-Grafana-Org-Id"), 10, 64); err == nil {
+		return orgId
+	}
+	if login, _, ok := r.BasicAuth(); ok {
+		if parsed := stubServiceUser.FindStringSubmatch(login); parsed != nil {
+			orgId, _ := strconv.ParseInt(parsed[1], 10, 64)
+			return orgId
+		}
+	}
+
+	return 0
+}
+
+// stubEntityOrganization returns the organization of a "d<orgId>" dashboard, "f<orgId>" folder
+// or "s<orgId>" datasource, -1 for any other name.
+func stubEntityOrganization(name string) int64 {
+	parsed := stubEntity.FindStringSubmatch(name)
+	if parsed == nil {
+		return -1
+	}
+	orgId, _ := strconv.ParseInt(parsed[1], 10, 64)
+
+	return orgId
+}
+
+// stubUserJSON returns the "user<id>" user, or the service user of the organization for the
+// ids above 1000.
+func stubUserJSON(id int64) string {
+	if id > 1000 {
+		return fmt.Sprintf(`{"id":%d,"login":"svc%d.stub","email":"svc%d.stub","orgId":%d}`, id, id-1000, id-1000, id-1000)
+	}
+
+	return fmt.Sprintf(`{"id":%d,"login":"user%d","email":"user%d@localhost","name":"User %d","orgId":1}`, id, id, id, id)
+}
+
+// newGrafanaStub answers the calls made by the organization and user routes. Every dashboard
+// ("d<orgId>"), folder ("f<orgId>") and datasource ("s<orgId>") belongs to a single organization
+// and is only found in the organization the request is scoped to, so a request made in the
+// organization of another one fails.
+func newGrafanaStub(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		orgId := stubOrganization(r)
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		segments := strings.Split(strings.TrimPrefix(path, "/api/"), "/")
+		last := segments[len(segments)-1]
+		id, _ := strconv.ParseInt(last, 10, 64)
+
+		var body struct {
+			Login     string                 `json:"login"`
+			Name      string                 `json:"name"`
+			Title     string                 `json:"title"`
+			Dashboard map[string]interface{} `json:"dashboard"`
+		}
+		if r.Body != nil {
+			json.NewDecoder(r.Body).Decode(&body)
+		}
+
+		// let the requests of the other organizations overlap this one
+		time.Sleep(time.Millisecond)
+
+		notFound := func() {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not found"}`)
+		}
+		// inOrganization refuses an entity of another organization than the request's
+		inOrganization := func(name string) bool {
+			if orgId == 0 || stubEntityOrganization(name) != orgId {
+				notFound()
+				return false
+			}
+			return true
+		}
+
+		switch {
+		// organizations
+		case r.Method == http.MethodGet && len(segments) == 2 && segments[0] == "orgs" && id > 0:
+			fmt.Fprintf(w, `{"id":%d,"name":"org%d"}`, id, id)
+		case r.Method == http.MethodGet && len(segments) == 3 && segments[0] == "orgs" && segments[1] == "name":
+			orgId, _ := strconv.ParseInt(strings.TrimPrefix(last, "org"), 10, 64)
+			fmt.Fprintf(w, `{"id":%d,"name":"org%d"}`, orgId, orgId)
+		case r.Method == http.MethodPost && len(segments) == 3 && segments[0] == "orgs" && segments[2] == "users":
+			fmt.Fprint(w, `{"message":"User added to organization"}`)
+		case r.Method == http.MethodGet && path == "/api/org/users":
+			fmt.Fprint(w, `[{"userId":1000,"login":"admin","email":"admin@localhost","role":"Admin"}]`)
+		case r.Method == http.MethodGet && path == "/api/user/orgs":
+			// the admin user is a member of every organization but stubNotMember
+			organizations := make([]string, 0, stubOrganizations)
+			for n := int64(1); n <= stubOrganizations; n++ {
+				if n != stubNotMember {
+					organizations = append(organizations, fmt.Sprintf(`{"orgId":%d,"name":"org%d","role":"Admin"}`, n, n))
+				}
+			}
+			fmt.Fprintf(w, `[%s]`, strings.Join(organizations, ","))
+
+		// users
+		case r.Method == http.MethodPost && path == "/api/admin/users":
+			if parsed := stubServiceUser.FindStringSubmatch(body.Login); parsed != nil {
+				serviceOrgId, _ := strconv.ParseInt(parsed[1], 10, 64)
+				fmt.Fprintf(w, `{"id":%d,"message":"User created"}`, 1000+serviceOrgId)
+				return
+			}
+			parsed := stubUser.FindStringSubmatch(body.Login)
+			if parsed == nil {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"message":"Unexpected login"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"id":%s,"message":"User created"}`, parsed[1])
+		case r.Method == http.MethodGet && path == "/api/users/lookup":
+			parsed := stubUser.FindStringSubmatch(r.URL.Query().Get("loginOrEmail"))
+			if parsed == nil {
+				notFound()
+				return
+			}
+			userId, _ := strconv.ParseInt(parsed[1], 10, 64)
+			fmt.Fprint(w, stubUserJSON(userId))
+		case r.Method == http.MethodGet && len(segments) == 2 && segments[0] == "users" && id > 0:
+			fmt.Fprint(w, stubUserJSON(id))
+		case r.Method == http.MethodGet && len(segments) == 3 && segments[0] == "users" && segments[2] == "orgs":
+			userId, _ := strconv.ParseInt(segments[1], 10, 64)
+			if userId > 1000 {
+				fmt.Fprintf(w, `[{"orgId":%d,"name":"org%d","role":"Admin"}]`, userId-1000, userId-1000)
+				return
+			}
+			fmt.Fprint(w, `[{"orgId":1,"name":"org1","role":"Viewer"}]`)
+		case r.Method == http.MethodPut && len(segments) == 4 && segments[0] == "admin" && segments[3] == "password":
+			fmt.Fprint(w, `{"message":"User password updated"}`)
+		case r.Method == http.MethodDelete && len(segments) == 3 && segments[0] == "admin" && segments[1] == "users":
+			fmt.Fprint(w, `{"message":"User deleted"}`)
+
+		// annotations
+		case r.Method == http.MethodPost && path == "/api/annotations":
+			if orgId == 0 {
+				notFound()
+				return
+			}
+			fmt.Fprintf(w, `{"id":%d,"message":"Annotation added"}`, 100*orgId)
+
+		// dashboards
+		case r.Method == http.MethodGet && path == "/api/search":
+			query := r.URL.Query().Get("query")
+			if query == "" || stubEntityOrganization(query) != orgId {
+				fmt.Fprint(w, `[]`)
+				return
+			}
+			fmt.Fprintf(w, `[{"id":%d,"uid":"d%d","title":"d%d","type":"dash-db"}]`, orgId, orgId, orgId)
+		case r.Method == http.MethodGet && len(segments) == 3 && segments[0] == "dashboards" && segments[1] == "uid":
+			if !inOrganization(last) {
+				return
+			}
+			fmt.Fprintf(w, `{"dashboard":{"id":%d,"uid":"%s","title":"%s","version":1},"meta":{"folderId":0,"version":1}}`, orgId, last, last)
+		case r.Method == http.MethodPost && path == "/api/dashboards/db":
+			title, _ := body.Dashboard["title"].(string)
+			if !inOrganization(title) {
+				return
+			}
+			fmt.Fprintf(w, `{"id":%d,"uid":"%s","status":"success","version":2,"message":"Dashboard added"}`, orgId, title)
+		case r.Method == http.MethodDelete && len(segments) == 3 && segments[0] == "dashboards" && segments[1] == "uid":
+			if !inOrganization(last) {
+				return
+			}
+			fmt.Fprintf(w, `{"id":%d,"title":"%s","message":"Dashboard %s deleted"}`, orgId, last, last)
+
+		// folders
+		case r.Method == http.MethodGet && len(segments) == 2 && segments[0] == "folders":
+			if !inOrganization(last) {
+				return
+			}
+			fmt.Fprintf(w, `{"id":%d,"uid":"%s","title":"%s","version":1}`, orgId, last, last)
+		case r.Method == http.MethodPost && path == "/api/folders":
+			if !inOrganization(body.Title) {
+				return
+			}
+			fmt.Fprintf(w, `{"id":%d,"uid":"%s","title":"%s","version":1}`, orgId, body.Title, body.Title)
+		case r.Method == http.MethodDelete && len(segments) == 2 && segments[0] == "folders":
+			if !inOrganization(last) {
+				return
+			}
+			fmt.Fprintf(w, `{"id":%d,"title":"%s","message":"Folder %s deleted"}`, orgId, last, last)
+
+		// datasources
+		case r.Method == http.MethodGet && path == "/api/datasources":
+			fmt.Fprintf(w, `[{"id":%d,"uid":"s%d","orgId":%d,"name":"s%d","type":"prometheus"}]`, orgId, orgId, orgId, orgId)
+		case r.Method == http.MethodGet && len(segments) >= 2 && segments[0] == "datasources":
+			if id > 0 {
+				last = "s" + last
+			}
+			if !inOrganization(last) {
+				return
+			}
+			fmt.Fprintf(w, `{"id":%d,"uid":"%s","orgId":%d,"name":"%s","type":"prometheus","version":1}`, orgId, last, orgId, last)
+		case r.Method == http.MethodPost && path == "/api/datasources":
+			if !inOrganization(body.Name) {
+				return
+			}
+			fmt.Fprintf(w, `{"id":%d,"name":"%s","message":"Datasource added","datasource":{"id":%d,"uid":"%s","orgId":%d,"name":"%s","version":1}}`, orgId, body.Name, orgId, body.Name, orgId, body.Name)
+		case r.Method == http.MethodDelete && len(segments) >= 2 && segments[0] == "datasources":
+			if id > 0 {
+				last = "s" + last
+			}
+			if !inOrganization(last) {
+				return
+			}
+			fmt.Fprintf(w, `{"id":%d,"message":"Data source deleted"}`, orgId)
+
+		default:
+			notFound()
+		}
+	}))
+}
+
+// routeCase is a request made for the organization (or user) n, check tells whether the
+// response is the one of this request.
+type routeCase struct {
+	name   string
+	method string
+	path   func(n int64) string
+	body   func(n int64) string
+	check  func(n int64, body []byte) error
+}
+
+// expectFields checks the JSON object (or the object under key) has the fields.
+func expectFields(key string, fields map[string]interface{}) func(n int64, body []byte) error {
+	return func(n int64, body []byte) error {
+		var object map[string]interface{}
+		err := json.Unmarshal(body, &object)
+		if err != nil {
+			return err
+		}
+		if key != "" {
+			object, _ = object[key].(map[string]interface{})
+		}
+		for field, format := range fields {
+			want := fmt.Sprintf(format.(string), n)
+			if got := fmt.Sprint(object[field]); got != want {
+				return fmt.Errorf("%s is %s instead of %s", field, got, want)
+			}
+		}
+		return nil
+	}
+}
+
+func expectTrue(n int64, body []byte) error {
+	if string(body) != "true" {
+		return fmt.Errorf("got %s", body)
+	}
+	return nil
+}
+
+func organizationPath(format string) func(n int64) string {
+	return func(n int64) string {
+		return fmt.Sprintf(format, n)
+	}
+}
+
+var routeCases = []routeCase{
+	{
+		name:   "list datasources",
+		method: http.MethodGet,
+		path:   organizationPath("/organizations/%[1]d/datasources/"),
+		check: func(n int64, body []byte) error {
+			var datasources []grafana.Datasource
+			err := json.Unmarshal(body, &datasources)
+			if err != nil {
+				return err
+			}
+			if len(datasources) != 1 || datasources[0].OrgId != n {
+				return fmt.Errorf("got %s", body)
+			}
+			return nil
+		},
+	},
+	{
+		name:   "get dashboard",
+		method: http.MethodGet,
+		path:   organizationPath("/organizations/%[1]d/dashboards/d%[1]d"),
+		check:  expectFields("dashboard", map[string]interface{}{"uid": "d%d"}),
+	},
+	{
+		name:   "create dashboard",
+		method: http.MethodPost,
+		path:   organizationPath("/organizations/%[1]d/dashboards/"),
+		body:   func(n int64) string { return fmt.Sprintf(`{"dashboard":{"title":"d%d"}}`, n) },
+		check:  expectFields("dashboard", map[string]interface{}{"title": "d%d"}),
+	},
+	{
+		name:   "delete dashboard",
+		method: http.MethodDelete,
+		path:   organizationPath("/organizations/%[1]d/dashboards/d%[1]d"),
+		check:  expectTrue,
+	},
+	{
+		name:   "create folder",
+		method: http.MethodPost,
+		path:   organizationPath("/organizations/%[1]d/folders/"),
+		body:   func(n int64) string { return fmt.Sprintf(`{"title":"f%d"}`, n) },
+		check:  expectFields("", map[string]interface{}{"uid": "f%d"}),
+	},
+	{
+		name:   "delete folder",
+		method: http.MethodDelete,
+		path:   organizationPath("/organizations/%[1]d/folders/f%[1]d"),
+		check:  expectTrue,
+	},
+	{
+		name:   "create datasource",
+		method: http.MethodPost,
+		path:   organizationPath("/organizations/%[1]d/datasources/"),
+		body:   func(n int64) string { return fmt.Sprintf(`{"name":"s%d","type":"prometheus"}`, n) },
+		check:  expectFields("", map[string]interface{}{"name": "s%d"}),
+	},
+	{
+		name:   "delete datasource",
+		method: http.MethodDelete,
+		path:   organizationPath("/organizations/%[1]d/datasources/s%[1]d"),
+		check:  expectTrue,
+	},
+	{
+		name:   "get user",
+		method: http.MethodGet,
+		path:   func(n int64) string { return fmt.Sprintf("/users/%d", n) },
+		check:  expectFields("", map[string]interface{}{"login": "user%d"}),
+	},
+	{
+		name:   "get user by login",
+		method: http.MethodGet,
+		path:   func(n int64) string { return fmt.Sprintf("/users/login=user%d", n) },
+		check:  expectFields("", map[string]interface{}{"id": "%d"}),
+	},
+	{
+		name:   "create user",
+		method: http.MethodPost,
+		path:   func(n int64) string { return "/users/" },
+		body:   func(n int64) string { return fmt.Sprintf(`{"login":"user%d","email":"user%d@localhost"}`, n, n) },
+		check:  expectFields("", map[string]interface{}{"id": "%d"}),
+	},
+	{
+		name:   "delete user",
+		method: http.MethodDelete,
+		path:   func(n int64) string { return fmt.Sprintf("/users/%d", n) },
+		check:  expectTrue,
+	},
+}
+
+// TestRoutesConcurrentRequests fires the routes in parallel at every organization (and user),
+// with both ORG_ACCESS ways, each response has to be the one of its own organization. It is
+// meant to be run with -race.
+func TestRoutesConcurrentRequests(t *testing.T) {
+	stub := newGrafanaStub(t)
+	defer stub.Close()
+
+	for _, mode := range []string{settings.OrgAccessHeader, settings.OrgAccessServiceUser} {
+		t.Run(mode, func(t *testing.T) {
+			f := flamego.New()
+			f.Map(grafana.NewClient(stub.URL, "admin", "admin"))
+			f.Map(newOrganizationAccess(settings.GrafanaBackendSettings{OrgAccess: mode, Login: "admin"}))
+			grafanaRoutes(f)
+
+			const requestsPerCase = 4
+
+			var wg sync.WaitGroup
+			errs := make(chan error, len(routeCases)*stubOrganizations*requestsPerCase)
+			for _, route := range routeCases {
+				for n := int64(1); n <= stubOrganizations; n++ {
+					for i := 0; i < requestsPerCase; i++ {
+						wg.Add(1)
+						go func(route routeCase, n int64) {
+							defer wg.Done()
+
+							var body *strings.Reader
+							if route.body != nil {
+								body = strings.NewReader(route.body(n))
+							} else {
+								body = strings.NewReader("")
+							}
+							req := httptest.NewRequest(route.method, route.path(n), body)
+							req.Header.Set("Content-Type", "application/json")
+							res := httptest.NewRecorder()
+							f.ServeHTTP(res, req)
+
+							if res.Code != http.StatusOK {
+								errs <- fmt.Errorf("%s %d: status %d, body: %s", route.name, n, res.Code, res.Body.String())
+								return
+							}
+							err := route.check(n, res.Body.Bytes())
+							if err != nil {
+								errs <- fmt.Errorf("%s %d: got the response of another request: %v", route.name, n, err)
+							}
+						}(route, n)
+					}
+				}
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestDashboardRoutesKeepHTMLCharacters checks the dashboard is returned the way it has been
+// sent, without <, > and & escaped by the JSON encoding.
+func TestDashboardRoutesKeepHTMLCharacters(t *testing.T) {
+	stub := newGrafanaStub(t)
+	defer stub.Close()
+
+	f := flamego.New()
+	f.Map(grafana.NewClient(stub.URL, "admin", "admin"))
+	f.Map(newOrganizationAccess(settings.GrafanaBackendSettings{OrgAccess: settings.OrgAccessHeader, Login: "admin"}))
+	grafanaRoutes(f)
+
+	description := `"description":"<b>CPU</b> & memory > 90%"`
+	req := httptest.NewRequest(http.MethodPost, "/organizations/1/dashboards/", strings.NewReader(`{"dashboard":{"title":"d1",`+description+`}}`))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	f.ServeHTTP(res, req)
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("Status %d, body: %s", res.Code, res.Body.String())
+	}
+	if !strings.Contains(res.Body.String(), description) {
+		t.Errorf("The dashboard is returned escaped: %s", res.Body.String())
+	}
+}
+
+// newMemberRoutes serves the routes against the stub with the ORG_ACCESS way, logins returns the
+// users other than the admin user the calls to the stub have been made as.
+func newMemberRoutes(t *testing.T, stub *httptest.Server, mode string) (f *flamego.Flame, logins func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var otherLogins []string
+	recorder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if login, _, ok := r.BasicAuth(); ok && login != "admin" {
+			mu.Lock()
+			otherLogins = append(otherLogins, login)
+			mu.Unlock()
+		}
+		stub.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(recorder.Close)
+
+	f = flamego.New()
+	f.Map(grafana.NewClient(recorder.URL, "admin", "admin"))
+	f.Map(newOrganizationAccess(settings.GrafanaBackendSettings{OrgAccess: mode, Login: "admin"}))
+	grafanaRoutes(f)
+
+	return f, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return otherLogins
+	}
+}
+
+// TestDashboardSearchAsMember checks the search of several organizations is made as the admin
+// user with X-Grafana-Org-Id in both ORG_ACCESS ways, without service users, and that the
+// organizations the admin user is not a member of are reported.
+func TestDashboardSearchAsMember(t *testing.T) {
+	stub := newGrafanaStub(t)
+	defer stub.Close()
+
+	for _, mode := range []string{settings.OrgAccessHeader, settings.OrgAccessServiceUser} {
+		t.Run(mode, func(t *testing.T) {
+			f, logins := newMemberRoutes(t, stub, mode)
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/dashboards/search?query=d3&organizations=3,%d", stubNotMember), nil)
+			res := httptest.NewRecorder()
+			f.ServeHTTP(res, req)
+
+			if res.Code != http.StatusMultiStatus {
+				t.Fatalf("Status %d, body: %s", res.Code, res.Body.String())
+			}
+			var result struct {
+				Hits []struct {
+					OrgId int64  `json:"orgId"`
+					Uid   string `json:"uid"`
+				} `json:"hits"`
+				Failures []struct {
+					OrgId int64 `json:"orgId"`
+				} `json:"failures"`
+			}
+			err := json.Unmarshal(res.Body.Bytes(), &result)
+			if err != nil {
+				t.Fatalf("Unmarshal %s: %v", res.Body.String(), err)
+			}
+			if len(result.Hits) != 1 || result.Hits[0].OrgId != 3 || result.Hits[0].Uid != "d3" {
+				t.Errorf("Hits of organization 3 expected: %s", res.Body.String())
+			}
+			if len(result.Failures) != 1 || result.Failures[0].OrgId != stubNotMember {
+				t.Errorf("Organization %d expected to fail: %s", stubNotMember, res.Body.String())
+			}
+			if got := logins(); len(got) > 0 {
+				t.Errorf("Requests made as %v instead of the admin user", got)
+			}
+		})
+	}
+}
+
+// TestAnnotationsAsMember checks the annotation of several organizations is created as the admin
+// user with X-Grafana-Org-Id in both ORG_ACCESS ways, without service users, and that the
+// organizations the admin user is not a member of are reported.
+func TestAnnotationsAsMember(t *testing.T) {
+	stub := newGrafanaStub(t)
+	defer stub.Close()
+
+	for _, mode := range []string{settings.OrgAccessHeader, settings.OrgAccessServiceUser} {
+		t.Run(mode, func(t *testing.T) {
+			f, logins := newMemberRoutes(t, stub, mode)
+
+			body := fmt.Sprintf(`{"organizations":[3,%d],"annotation":{"text":"Deployed api v1.2.3","tags":["deploy"]}}`, stubNotMember)
+			req := httptest.NewRequest(http.MethodPost, "/annotations", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			res := httptest.NewRecorder()
+			f.ServeHTTP(res, req)
+
+			if res.Code != http.StatusMultiStatus {
+				t.Fatalf("Status %d, body: %s", res.Code, res.Body.String())
+			}
+			var results []struct {
+				OrgId        int64 `json:"orgId"`
+				AnnotationId int64 `json:"annotationId"`
+				Status       bool  `json:"status"`
+			}
+			err := json.Unmarshal(res.Body.Bytes(), &results)
+			if err != nil {
+				t.Fatalf("Unmarshal %s: %v", res.Body.String(), err)
+			}
+			if len(results) != 2 || !results[0].Status || results[0].AnnotationId != 300 || results[1].Status {
+				t.Errorf("Organization 3 expected to succeed and %d to fail: %s", stubNotMember, res.Body.String())
+			}
+			if got := logins(); len(got) > 0 {
+				t.Errorf("Requests made as %v instead of the admin user", got)
+			}
+		})
+	}
+}
+
+// TestOrganizationUsersRefusesEmptyList checks an empty or missing member list does not
+// empty the organization unless it is asked for.
+func TestOrganizationUsersRefusesEmptyList(t *testing.T) {
+	stub := newGrafanaStub(t)
+	defer stub.Close()
+
+	f := flamego.New()
+	f.Map(grafana.NewClient(stub.URL, "admin", "admin"))
+	f.Map(newOrganizationAccess(settings.GrafanaBackendSettings{OrgAccess: settings.OrgAccessHeader, Login: "admin"}))
+	grafanaRoutes(f)
+
+	for _, body := range []string{`[]`, `null`} {
+		req := httptest.NewRequest(http.MethodPut, "/organizations/3/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		f.ServeHTTP(res, req)
+
+		if res.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400, body: %s", body, res.Code, res.Body.String())
+		}
+	}
+}
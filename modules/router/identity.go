@@ -0,0 +1,26 @@
+package router
+
+import (
+	"github.com/flamego/flamego"
+
+	"grafana-adapter/modules/settings"
+)
+
+// defaultIdentity names the adapter in Grafana when it is not protected by basic auth
+const defaultIdentity = "grafana-adapter"
+
+// identity returns the name the caller of the adapter is known by in Grafana, the basic
+// auth login of the request when the adapter has checked it (server LOGIN and PASSWORD set),
+// a login the adapter does not check being anyone's to claim.
+func identity(c flamego.Context) string {
+	if settings.Server.Login == "" || settings.Server.Password == "" {
+		return defaultIdentity
+	}
+
+	login, _, ok := c.Request().BasicAuth()
+	if !ok || login == "" {
+		return defaultIdentity
+	}
+
+	return login
+}
@@ -0,0 +1,44 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flamego/flamego"
+
+	"grafana-adapter/modules/settings"
+)
+
+func TestIdentity(t *testing.T) {
+	server := settings.Server
+	defer func() { settings.Server = server }()
+
+	cases := []struct {
+		name            string
+		login, password string
+		want            string
+	}{
+		{"unchecked login", "", "", defaultIdentity},
+		{"checked login", "ops", "secret", "ops"},
+	}
+	for _, identityCase := range cases {
+		t.Run(identityCase.name, func(t *testing.T) {
+			settings.Server.Login, settings.Server.Password = identityCase.login, identityCase.password
+
+			f := flamego.New()
+			f.Get("/", func(c flamego.Context) string {
+				return identity(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.SetBasicAuth("ops", "secret")
+			res := httptest.NewRecorder()
+			f.ServeHTTP(res, req)
+
+			if res.Body.String() != identityCase.want {
+				t.Errorf("identity %q, want %q", res.Body.String(), identityCase.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package router
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/flamego/flamego"
+
+	grafana "grafana-adapter/modules/external/grafana/apiv1"
+	"grafana-adapter/modules/settings"
+)
+
+type instanceHealth struct {
+	Name     string `json:"name"`
+	Url      string `json:"url"`
+	Database string `json:"database,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func newGrafanaClients(logger *log.Logger) map[string]*grafana.Client {
+	clients := make(map[string]*grafana.Client, len(settings.GrafanaInstances))
+	for name, instance := range settings.GrafanaInstances {
+		options := []grafana.ClientOption{grafana.WithLogger(logger)}
+		if instance.Token != "" {
+			options = append(options, grafana.WithToken(instance.Token))
+		}
+		clients[name] = grafana.NewClient(instance.URL(), instance.Login, instance.Password, options...)
+	}
+
+	return clients
+}
+
+func instanceNames(clients map[string]*grafana.Client) []string {
+	names := make([]string, 0, len(clients))
+	for name := range clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func instanceRoutes(f *flamego.Flame, clients map[string]*grafana.Client, accesses map[string]*organizationAccess) {
+	/*
+	   - INSTANCES -
+	   Retrieving all configured grafana instances:
+	   GET
+	   .../instances
+
+	   Any route of a single instance (users, organizations, dashboards, ...):
+	   .../instances/{name}/... (.../instances/eu/organizations/ || .../instances/default/users/1)
+
+	   Checking health of every instance:
+	   GET
+	   .../health
+	*/
+	f.Group("/instances", func() {
+		f.Get("", func(c flamego.Context) string {
+			instances := []instanceHealth{}
+			for _, name := range instanceNames(clients) {
+				instances = append(instances, instanceHealth{Name: name, Url: clients[name].URL()})
+			}
+
+			jsonResponse, err := json.Marshal(instances)
+			if err != nil {
+				log.Print("Got error: " + err.Error())
+				c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+				return "null"
+			}
+			c.ResponseWriter().Header().Add("Content-Type", "application/json")
+			return string(jsonResponse)
+		})
+		f.Group("/{instance}", func() {
+			grafanaRoutes(f)
+		}, func(c flamego.Context) {
+			client, ok := clients[c.Param("instance")]
+			if !ok {
+				c.ResponseWriter().WriteHeader(http.StatusNotFound)
+				return
+			}
+			c.Map(client)
+			c.Map(accesses[c.Param("instance")])
+		})
+	})
+
+	f.Get("/health", func(c flamego.Context) string {
+		names := instanceNames(clients)
+		instances := make([]instanceHealth, len(names))
+
+		var wg sync.WaitGroup
+		for i, name := range names {
+			wg.Add(1)
+			go func(i int, name string) {
+				defer wg.Done()
+				instances[i] = instanceHealth{Name: name, Url: clients[name].URL()}
+				health, err := clients[name].GetHealth(c.Request().Context())
+				if err != nil {
+					instances[i].Error = err.Error()
+					return
+				}
+				instances[i].Database = health.Database
+				instances[i].Version = health.Version
+			}(i, name)
+		}
+		wg.Wait()
+
+		status := http.StatusOK
+		for _, instance := range instances {
+			if instance.Error != "" || instance.Database != "ok" {
+				status = http.StatusServiceUnavailable
+			}
+		}
+
+		jsonResponse, err := json.Marshal(instances)
+		if err != nil {
+			log.Print("Got error: " + err.Error())
+			c.ResponseWriter().WriteHeader(http.StatusInternalServerError)
+			return "null"
+		}
+		c.ResponseWriter().Header().Add("Content-Type", "application/json")
+		c.ResponseWriter().WriteHeader(status)
+		return string(jsonResponse)
+	})
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// marshalUnescaped encodes the value as json.Marshal does, except that <, > and & are left
+// as they are, so that a dashboard is returned the way Grafana stores it.
+func marshalUnescaped(v interface{}) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimRight(buffer.Bytes(), "\n"), nil
+}
@@ -0,0 +1,182 @@
+package router
+
+import (
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+
+	grafana "grafana-adapter/modules/external/grafana/apiv1"
+	"grafana-adapter/modules/settings"
+	"grafana-adapter/modules/util"
+)
+
+// errNotMember refuses an organization the admin user has not joined to the routes spanning
+// several organizations.
+var errNotMember = errors.New("The admin user is not a member of the organization")
+
+// organizationAccess scopes the client of a grafana instance to a single organization
+// for the organization routes (dashboards, folders, datasources, ...), the way set by
+// the ORG_ACCESS parameter of the instance.
+type organizationAccess struct {
+	mode  string
+	login string
+	// managedPrefix names the organizations the adapter owns (MANAGED_ORG_PREFIX)
+	managedPrefix string
+	// members holds ids of the organizations the admin user is known to be a member of
+	members sync.Map
+}
+
+func newOrganizationAccess(instance settings.GrafanaBackendSettings) *organizationAccess {
+	return &organizationAccess{
+		mode:          instance.OrgAccess,
+		login:         instance.Login,
+		managedPrefix: instance.ManagedOrgPrefix,
+	}
+}
+
+func newOrganizationAccesses() map[string]*organizationAccess {
+	accesses := make(map[string]*organizationAccess, len(settings.GrafanaInstances))
+	for name, instance := range settings.GrafanaInstances {
+		accesses[name] = newOrganizationAccess(instance)
+	}
+
+	return accesses
+}
+
+// client returns the client scoped to the organization.
+func (a *organizationAccess) client(ctx context.Context, client *grafana.Client, organization grafana.Organization) (*grafana.Client, error) {
+	// a token can neither join organizations nor create users, Grafana accepts
+	// the header only for the organization the token belongs to
+	if client.UsesToken() {
+		return client.ForOrganization(organization.Id), nil
+	}
+
+	if a.mode == settings.OrgAccessServiceUser {
+		return a.serviceUserClient(ctx, client, organization)
+	}
+
+	// X-Grafana-Org-Id is only honoured for members of the organization,
+	// so the admin user joins it once
+	if _, ok := a.members.Load(organization.Id); !ok {
+		err := a.join(ctx, client, organization)
+		if err != nil {
+			return nil, err
+		}
+		a.members.Store(organization.Id, true)
+	}
+
+	return client.ForOrganization(organization.Id), nil
+}
+
+// join makes the admin user an Admin of the organization, the role of an admin user
+// that is already a member of it is raised to Admin.
+func (a *organizationAccess) join(ctx context.Context, client *grafana.Client, organization grafana.Organization) error {
+	_, err := client.AddUserToOrganization(ctx, &grafana.User{Login: a.login}, &organization, "Admin")
+	if !errors.Is(err, grafana.ErrConflict) {
+		return err
+	}
+
+	member, err := client.ForOrganization(organization.Id).LookupOrganizationUser(ctx, a.login, "")
+	if err != nil {
+		return err
+	}
+	if member.Role == "Admin" {
+		return nil
+	}
+
+	_, err = client.UpdateUserInOrganization(ctx, &grafana.User{Id: member.Id, Login: member.Login}, &organization, "Admin")
+
+	return err
+}
+
+// memberClient returns the client of the admin user scoped to the organization with the
+// X-Grafana-Org-Id header, whatever the ORG_ACCESS mode: the admin user does not join it and
+// no service user is set up, an organization the admin user is not a member of is refused.
+// It serves the routes spanning several organizations.
+func (a *organizationAccess) memberClient(ctx context.Context, client *grafana.Client, organization grafana.Organization) (*grafana.Client, error) {
+	if client.UsesToken() {
+		return client.ForOrganization(organization.Id), nil
+	}
+
+	if _, ok := a.members.Load(organization.Id); ok {
+		return client.ForOrganization(organization.Id), nil
+	}
+
+	organizations, err := client.GetCurrentUserOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	member := false
+	for _, userOrganization := range organizations {
+		// only an Admin membership spares client the role check when joining
+		if userOrganization.Role == "Admin" {
+			a.members.Store(userOrganization.Id, true)
+		}
+		if userOrganization.Id == organization.Id {
+			member = true
+		}
+	}
+	if !member {
+		return nil, errNotMember
+	}
+
+	return client.ForOrganization(organization.Id), nil
+}
+
+// forget drops the membership of the admin user in the organization, it joins the
+// organization again the next time a client is scoped to it.
+func (a *organizationAccess) forget(organization grafana.Organization) {
+	a.members.Delete(organization.Id)
+}
+
+// serviceUserClient creates or resets the password of the "svc<id>.<md5>" admin user
+// of the organization and returns the client authenticated as this user.
+func (a *organizationAccess) serviceUserClient(ctx context.Context, client *grafana.Client, organization grafana.Organization) (*grafana.Client, error) {
+	serviceUser := grafana.User{
+		Login:    "svc" + strconv.FormatInt(organization.Id, 10) + "." + fmt.Sprintf("%x", md5.Sum([]byte(organization.Name))),
+		Password: util.RandString(12),
+		OrgId:    organization.Id,
+	}
+
+	_, err := client.CreateUser(ctx, &serviceUser)
+	if errors.Is(err, grafana.ErrConflict) {
+		_, err = client.GetUser(ctx, &serviceUser)
+		if err != nil {
+			log.Print("Got error: " + err.Error())
+		}
+		_, err = client.UpdateUserPassword(ctx, &serviceUser)
+		if err != nil {
+			log.Print("Got error: " + err.Error())
+		}
+	} else if err != nil {
+		log.Print("Got error: " + err.Error())
+	}
+
+	if serviceUser.Id == 0 {
+		return nil, errors.New("Unable to set up service user " + serviceUser.Login)
+	}
+
+	serviceUserOrgs := []grafana.UserOrganization{{
+		Id:   organization.Id,
+		Name: organization.Name,
+		Role: "Admin",
+	}}
+
+	_, err = client.SetUserOrganizations(ctx, &serviceUser, &serviceUserOrgs)
+	if err != nil {
+		log.Print("Got error: " + err.Error())
+	}
+
+	return client.AsUser(serviceUser.Login, serviceUser.Password), nil
+}
+
+// organizationClient is the client scoped to the organization of the request,
+// mapped apart from the *grafana.Client of the instance.
+type organizationClient struct {
+	*grafana.Client
+}
@@ -0,0 +1,72 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	grafana "grafana-adapter/modules/external/grafana/apiv1"
+	"grafana-adapter/modules/settings"
+)
+
+// TestOrganizationAccessJoinsAsAdmin checks that in header mode the admin user that is already
+// a member of the organization has its role raised to Admin, and is left alone when it is one.
+func TestOrganizationAccessJoinsAsAdmin(t *testing.T) {
+	for _, role := range []string{"Viewer", "Editor", "Admin"} {
+		t.Run(role, func(t *testing.T) {
+			var mu sync.Mutex
+			var raisedTo []string
+
+			stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/api/orgs/3/users":
+					w.WriteHeader(http.StatusConflict)
+					fmt.Fprint(w, `{"message":"User is already member of this organization"}`)
+				case r.Method == http.MethodGet && r.URL.Path == "/api/org/users" && r.Header.Get("X-Grafana-Org-Id") == "3":
+					fmt.Fprintf(w, `[{"userId":1,"login":"admin","email":"admin@localhost","orgId":3,"role":"%s"}]`, role)
+				case r.Method == http.MethodPatch && r.URL.Path == "/api/orgs/3/users/1":
+					var body struct {
+						Role string `json:"role"`
+					}
+					json.NewDecoder(r.Body).Decode(&body)
+					mu.Lock()
+					raisedTo = append(raisedTo, body.Role)
+					mu.Unlock()
+					fmt.Fprint(w, `{"message":"Organization user updated"}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"message":"Not found"}`)
+				}
+			}))
+			defer stub.Close()
+
+			access := newOrganizationAccess(settings.GrafanaBackendSettings{OrgAccess: settings.OrgAccessHeader, Login: "admin"})
+			client := grafana.NewClient(stub.URL, "admin", "admin")
+			organization := grafana.Organization{Id: 3, Name: "org3"}
+
+			// the second call is served from the memberships of the admin user
+			for i := 0; i < 2; i++ {
+				scoped, err := access.client(context.Background(), client, organization)
+				if err != nil {
+					t.Fatalf("client: %v", err)
+				}
+				if scoped.OrganizationId() != 3 {
+					t.Fatalf("Client scoped to organization %d", scoped.OrganizationId())
+				}
+			}
+
+			want := []string{"Admin"}
+			if role == "Admin" {
+				want = nil
+			}
+			if fmt.Sprint(raisedTo) != fmt.Sprint(want) {
+				t.Errorf("Role raised to %v, want %v", raisedTo, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/flamego/flamego"
+
+	grafana "grafana-adapter/modules/external/grafana/apiv1"
+)
+
+// defaultPerPage is the page size of the list routes when only the page query parameter is given
+const defaultPerPage = 100
+
+// errInvalidPage is answered with 400 by the list routes
+var errInvalidPage = errors.New("The page and perpage query parameters must be positive integers")
+
+// pageQuery reads the page and perpage query parameters of a list route. paged is false when
+// the request has none of them, the whole list is then returned.
+func pageQuery(c flamego.Context) (page grafana.Page, paged bool, err error) {
+	pageParam, perPageParam := c.Query("page"), c.Query("perpage")
+	if pageParam == "" && perPageParam == "" {
+		return grafana.Page{}, false, nil
+	}
+
+	page = grafana.Page{Page: 1, PerPage: defaultPerPage}
+	if pageParam != "" {
+		page.Page, err = strconv.Atoi(pageParam)
+		if err != nil || page.Page < 1 {
+			return page, true, errInvalidPage
+		}
+	}
+	if perPageParam != "" {
+		page.PerPage, err = strconv.Atoi(perPageParam)
+		if err != nil || page.PerPage < 1 {
+			return page, true, errInvalidPage
+		}
+	}
+
+	return page, true, nil
+}
+
+// pageBounds returns the bounds of the page within a list of length items fetched at once.
+func pageBounds(length int, page grafana.Page) (start, end int) {
+	start = (page.Page - 1) * page.PerPage
+	if start > length {
+		start = length
+	}
+	end = start + page.PerPage
+	if end > length {
+		end = length
+	}
+
+	return start, end
+}
+
+// writePageHeaders sets the X-Total-Count header when total is known (total >= 0) and the
+// Link header with the first, prev and next pages of the list.
+func writePageHeaders(c flamego.Context, page grafana.Page, count int, total int64) {
+	if total >= 0 {
+		c.ResponseWriter().Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	}
+
+	hasNext := count == page.PerPage
+	if total >= 0 {
+		hasNext = int64(page.Page)*int64(page.PerPage) < total
+	}
+
+	links := []string{pageLink(c.Request().Request, page.PerPage, 1, "first")}
+	if page.Page > 1 {
+		links = append(links, pageLink(c.Request().Request, page.PerPage, page.Page-1, "prev"))
+	}
+	if hasNext {
+		links = append(links, pageLink(c.Request().Request, page.PerPage, page.Page+1, "next"))
+	}
+	c.ResponseWriter().Header().Set("Link", strings.Join(links, ", "))
+}
+
+func pageLink(r *http.Request, perPage, page int, rel string) string {
+	q := r.URL.Query()
+	q.Set("page", strconv.Itoa(page))
+	q.Set("perpage", strconv.Itoa(perPage))
+
+	return "<" + r.URL.Path + "?" + q.Encode() + `>; rel="` + rel + `"`
+}
@@ -0,0 +1,41 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/flamego/flamego"
+
+	"grafana-adapter/modules/settings"
+)
+
+// requestTimeout bounds the context of the incoming request, which every grafana
+// call of the request is made with, by settings.Server.RequestTimeout or by the
+// shorter duration of the X-Request-Timeout header. The context is cancelled as
+// well when the client disconnects.
+func requestTimeout(c flamego.Context) {
+	timeout := settings.Server.RequestTimeout
+
+	if header := c.Request().Header.Get("X-Request-Timeout"); header != "" {
+		requested, err := time.ParseDuration(header)
+		if err != nil || requested <= 0 {
+			c.ResponseWriter().WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if timeout <= 0 || requested < timeout {
+			timeout = requested
+		}
+	}
+
+	if timeout <= 0 {
+		c.Next()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+	defer cancel()
+
+	c.Request().Request = c.Request().WithContext(ctx)
+	c.Next()
+}
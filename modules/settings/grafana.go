@@ -1,21 +1,126 @@
 package settings
 
-var GrafanaBackend = struct {
-	Port     int
-	Host     string
-	Login    string
-	Password string
-}{
-	Port:     3000,
-	Host:     "localhost",
-	Login:    "admin",
-	Password: "admin",
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	ini "gopkg.in/ini.v1"
+)
+
+// DefaultGrafanaInstance is the name the [grafana] section is registered under in GrafanaInstances.
+const DefaultGrafanaInstance = "default"
+
+// Ways organization scoped calls (dashboards, folders, datasources, ...) are made
+const (
+	// OrgAccessHeader makes the calls as the admin user with the X-Grafana-Org-Id header (default)
+	OrgAccessHeader = "header"
+	// OrgAccessServiceUser makes the calls as a per-organization "svc<id>.<md5>" admin user
+	OrgAccessServiceUser = "service_user"
+)
+
+type GrafanaBackendSettings struct {
+	Port      int
+	Host      string
+	Login     string
+	Password  string
+	Token     string
+	OrgAccess string
+	// ManagedOrgPrefix names the organizations the adapter owns, the only ones it replaces the memberships of
+	ManagedOrgPrefix string
+}
+
+// URL returns the base url of the Grafana instance.
+func (s GrafanaBackendSettings) URL() string {
+	return "http://" + s.Host + ":" + strconv.Itoa(s.Port)
 }
 
+var GrafanaBackend = GrafanaBackendSettings{
+	Port:      3000,
+	Host:      "localhost",
+	Login:     "admin",
+	Password:  "admin",
+	OrgAccess: OrgAccessHeader,
+}
+
+// GrafanaInstances holds every configured Grafana backend by name: the [grafana] section
+// as DefaultGrafanaInstance and every [grafana.<name>] section as <name>.
+var GrafanaInstances = map[string]GrafanaBackendSettings{}
+
 func getGrafanaBackendConfigParams() {
 	sec := Cfg.Section("grafana")
 	GrafanaBackend.Port = sec.Key("PORT").MustInt(3000)
 	GrafanaBackend.Host = sec.Key("HOST").MustString("localhost")
 	GrafanaBackend.Login = sec.Key("LOGIN").MustString("admin")
 	GrafanaBackend.Password = sec.Key("PASSWORD").MustString("admin")
+	GrafanaBackend.Token = getGrafanaToken(sec)
+	GrafanaBackend.OrgAccess = sec.Key("ORG_ACCESS").In(OrgAccessHeader, []string{OrgAccessHeader, OrgAccessServiceUser})
+	GrafanaBackend.ManagedOrgPrefix = sec.Key("MANAGED_ORG_PREFIX").String()
+
+	GrafanaInstances = map[string]GrafanaBackendSettings{
+		DefaultGrafanaInstance: GrafanaBackend,
+	}
+
+	// Named instances fall back to the [grafana] values for every parameter they omit
+	for _, sec := range Cfg.Sections() {
+		name := strings.TrimPrefix(sec.Name(), "grafana.")
+		if name == sec.Name() || name == "" {
+			continue
+		}
+		if name == DefaultGrafanaInstance {
+			log.Fatalf("Failed to load [%s]: the [grafana] section is already the %q instance", sec.Name(), DefaultGrafanaInstance)
+		}
+
+		// A block with its own credentials does not take the [grafana] token
+		token := getGrafanaToken(sec)
+		if (hasOwnKey(sec, "LOGIN") || hasOwnKey(sec, "PASSWORD")) && !hasOwnKey(sec, "TOKEN") && !hasOwnKey(sec, "TOKEN_FILE") {
+			token = ""
+		}
+
+		GrafanaInstances[name] = GrafanaBackendSettings{
+			Port:             sec.Key("PORT").MustInt(GrafanaBackend.Port),
+			Host:             sec.Key("HOST").MustString(GrafanaBackend.Host),
+			Login:            sec.Key("LOGIN").MustString(GrafanaBackend.Login),
+			Password:         sec.Key("PASSWORD").MustString(GrafanaBackend.Password),
+			Token:            token,
+			OrgAccess:        sec.Key("ORG_ACCESS").In(GrafanaBackend.OrgAccess, []string{OrgAccessHeader, OrgAccessServiceUser}),
+			ManagedOrgPrefix: sec.Key("MANAGED_ORG_PREFIX").MustString(GrafanaBackend.ManagedOrgPrefix),
+		}
+	}
+}
+
+// getGrafanaToken returns the TOKEN of the section or the content of its TOKEN_FILE,
+// the keys of the section itself taking precedence over the inherited ones.
+func getGrafanaToken(sec *ini.Section) string {
+	token := sec.Key("TOKEN").String()
+	if hasOwnKey(sec, "TOKEN_FILE") && !hasOwnKey(sec, "TOKEN") {
+		token = ""
+	}
+	if token != "" {
+		return token
+	}
+
+	tokenFile := sec.Key("TOKEN_FILE").String()
+	if tokenFile == "" {
+		return ""
+	}
+
+	content, err := os.ReadFile(tokenFile)
+	if err != nil {
+		log.Fatalf("Failed to read [%s] TOKEN_FILE: %v", sec.Name(), err)
+	}
+
+	return strings.TrimSpace(string(content))
+}
+
+// hasOwnKey reports whether the key is set in the section itself rather than inherited from its parent.
+func hasOwnKey(sec *ini.Section, name string) bool {
+	for _, key := range sec.KeyStrings() {
+		if key == name {
+			return true
+		}
+	}
+
+	return false
 }
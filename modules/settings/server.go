@@ -1,15 +1,19 @@
 package settings
 
+import "time"
+
 var Server = struct {
-	Port     int
-	Host     string
-	Login    string
-	Password string
+	Port           int
+	Host           string
+	Login          string
+	Password       string
+	RequestTimeout time.Duration
 }{
-	Port:     80,
-	Host:     "localhost",
-	Login:    "",
-	Password: "",
+	Port:           80,
+	Host:           "localhost",
+	Login:          "",
+	Password:       "",
+	RequestTimeout: 60 * time.Second,
 }
 
 func getServerConfigParams() {
@@ -18,4 +22,5 @@ func getServerConfigParams() {
 	Server.Host = sec.Key("HOST").MustString("localhost")
 	Server.Login = sec.Key("LOGIN").MustString("")
 	Server.Password = sec.Key("PASSWORD").MustString("")
+	Server.RequestTimeout = sec.Key("REQUEST_TIMEOUT").MustDuration(60 * time.Second)
 }
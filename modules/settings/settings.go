@@ -45,19 +45,19 @@ func loadFromConf(allowEmpty bool, extraConfig string) {
 
 	isFile, err := util.IsFile(CustomConf)
 	if err != nil {
-		log.Fatal("Unable to check if %s is a file. Error: %v", CustomConf, err)
+		log.Fatalf("Unable to check if %s is a file. Error: %v", CustomConf, err)
 	}
 	if isFile {
 		if err := Cfg.Append(CustomConf); err != nil {
-			log.Fatal("Failed to load custom conf '%s': %v", CustomConf, err)
+			log.Fatalf("Failed to load custom conf '%s': %v", CustomConf, err)
 		}
 	} else if !allowEmpty {
-		log.Fatal("Unable to find configuration file: %q.\nEnsure you are running in the correct environment or set the correct configuration file with -c.", CustomConf)
+		log.Fatalf("Unable to find configuration file: %q.\nEnsure you are running in the correct environment or set the correct configuration file with -c.", CustomConf)
 	} // else: no config file, a config file might be created at CustomConf later (might not)
 
 	if extraConfig != "" {
 		if err = Cfg.Append([]byte(extraConfig)); err != nil {
-			log.Fatal("Unable to append more config: %v", err)
+			log.Fatalf("Unable to append more config: %v", err)
 		}
 	}
 }
@@ -99,16 +99,16 @@ func getWorkPath(AppPath string) string {
 func createPIDFile(pidPath string) {
 	currentPid := os.Getpid()
 	if err := os.MkdirAll(filepath.Dir(pidPath), os.ModePerm); err != nil {
-		log.Fatal("Failed to create PID folder: %v", err)
+		log.Fatalf("Failed to create PID folder: %v", err)
 	}
 
 	file, err := os.Create(pidPath)
 	if err != nil {
-		log.Fatal("Failed to create PID file: %v", err)
+		log.Fatalf("Failed to create PID file: %v", err)
 	}
 	defer file.Close()
 	if _, err := file.WriteString(strconv.FormatInt(int64(currentPid), 10)); err != nil {
-		log.Fatal("Failed to write PID information: %v", err)
+		log.Fatalf("Failed to write PID information: %v", err)
 	}
 }
 
@@ -135,7 +135,7 @@ func SetConf(providedCustom, providedConf, providedWorkPath string) {
 		CustomConf = path.Join(CustomPath, "config.ini")
 	} else if !filepath.IsAbs(CustomConf) {
 		CustomConf = path.Join(CustomPath, CustomConf)
-		log.Print("Using 'custom' directory as relative origin for configuration file: '%s'", CustomConf)
+		log.Printf("Using 'custom' directory as relative origin for configuration file: '%s'", CustomConf)
 	}
 }
 
@@ -155,7 +155,7 @@ func init() {
 
 	var err error
 	if AppPath, err = getAppPath(); err != nil {
-		log.Fatal("Failed to get app path: %v", err)
+		log.Fatalf("Failed to get app path: %v", err)
 	}
 	AppWorkPath = getWorkPath(AppPath)
 }
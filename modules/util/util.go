@@ -3,6 +3,7 @@ package util
 import (
 	"math/rand"
 	"os"
+	"sync"
 	"time"
 	"unsafe"
 )
@@ -20,7 +21,11 @@ func IsFile(filePath string) (bool, error) {
 	return false, err
 }
 
-var src = rand.NewSource(time.Now().UnixNano())
+// src is shared by concurrent requests, a rand.Source is not safe for concurrent use
+var (
+	src   = rand.NewSource(time.Now().UnixNano())
+	srcMu sync.Mutex
+)
 
 const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890-"
 const (
@@ -31,6 +36,8 @@ const (
 
 func RandString(n int) string {
 	b := make([]byte, n)
+	srcMu.Lock()
+	defer srcMu.Unlock()
 	// A src.Int63() generates 63 random bits, enough for letterIdxMax characters!
 	for i, cache, remain := n-1, src.Int63(), letterIdxMax; i >= 0; {
 		if remain == 0 {